@@ -1,5 +1,9 @@
 module github.com/xgfone/go-http-matcher
 
-require github.com/xgfone/go-toolkit v0.1.1
+require (
+	github.com/xgfone/go-toolkit v0.1.1
+	golang.org/x/crypto v0.33.0
+	golang.org/x/net v0.34.0
+)
 
 go 1.22
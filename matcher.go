@@ -18,6 +18,7 @@ package matcher
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 var (
@@ -43,14 +44,42 @@ func (f MatchFunc) Match(r *http.Request) bool { return f(r) }
 
 type matcher struct {
 	MatchFunc
-	desc string
-	prio int
+	desc  string
+	canon string
+	conjs []string
+	prio  int
 }
 
 func (m matcher) String() string { return m.desc }
 func (m matcher) Priority() int  { return m.prio }
 
+func (m matcher) conjuncts() []string { return m.conjs }
+
+func (m matcher) canonical() string {
+	if m.canon == "" {
+		return m.desc
+	}
+	return m.canon
+}
+
 // New returns a request matcher.
 func New(prio int, desc string, match MatchFunc) Matcher {
 	return matcher{prio: prio, desc: desc, MatchFunc: match}
 }
+
+// newc is the same as New, but also has the canonical description.
+func newc(prio int, desc, canon string, match MatchFunc) Matcher {
+	return matcher{prio: prio, desc: desc, canon: canon, MatchFunc: match}
+}
+
+// newcs is the same as New, but the matcher is the conjunction of the
+// conditions with the canonical descriptions conjs, such as Headerm,
+// which are flattened by Canonical into the enclosing And.
+func newcs(prio int, desc string, conjs []string, match MatchFunc) Matcher {
+	conjs = sortedset(conjs)
+	canon := conjs[0]
+	if len(conjs) > 1 {
+		canon = "(" + strings.Join(conjs, andsep) + ")"
+	}
+	return matcher{prio: prio, desc: desc, canon: canon, conjs: conjs, MatchFunc: match}
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Accepts returns a new matcher that checks whether the header "Accept"
+// accepts one of the specified media types, such as "application/json".
+//
+// The header is parsed by RFC 9110, which supports the wildcards
+// such as "*/*" and "text/*", the media type parameters, and the weight
+// "q". The media type whose weight is 0 is not acceptable. If the request
+// has no the header "Accept", it accepts any media type.
+//
+// The invalid media type, such as "json", is ignored.
+//
+// If types is empty or all invalid, return nil instead of an error.
+func Accepts(types ...string) Matcher {
+	offers := make([]mediaRange, 0, len(types))
+	for _, _type := range types {
+		if offer := parseMediaRange(_type); offer.Valid() {
+			offers = append(offers, offer)
+		}
+	}
+	if len(offers) == 0 {
+		return nil
+	}
+
+	descs := mediaRangeStrings(offers)
+	desc := argsdesc("Accepts", descs...)
+	canon := argsdesc("Accepts", sortedset(descs)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		ranges := parseAccept(r.Header.Values("Accept"))
+		for _, offer := range offers {
+			if acceptQuality(ranges, offer) > 0 {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// AcceptsBest returns a new matcher that checks whether the media type
+// is the winner negotiated by the header "Accept" among the offers
+// provided by the server, which has the highest weight, and the earlier
+// offer wins when having the same weight.
+//
+// If _type is not in offers, it will be appended to the end.
+// The invalid offer, such as "json", is ignored.
+//
+// If _type is empty or invalid, return nil instead of an error.
+func AcceptsBest(_type string, offers ...string) Matcher {
+	best := parseMediaRange(_type)
+	if !best.Valid() {
+		return nil
+	}
+
+	_offers := make([]mediaRange, 0, len(offers)+1)
+	for _, offer := range offers {
+		if _offer := parseMediaRange(offer); _offer.Valid() {
+			_offers = append(_offers, _offer)
+		}
+	}
+	if !containsMediaRange(_offers, best) {
+		_offers = append(_offers, best)
+	}
+
+	desc := fmt.Sprintf("AcceptsBest(`%s`,[`%s`])", best.String(),
+		strings.Join(mediaRangeStrings(_offers), "`,`"))
+	return New(PriorityHeader, desc, func(r *http.Request) bool {
+		ranges := parseAccept(r.Header.Values("Accept"))
+
+		winner, quality := -1, 0.0
+		for i, offer := range _offers {
+			if q := acceptQuality(ranges, offer); q > quality {
+				winner, quality = i, q
+			}
+		}
+		return winner > -1 && _offers[winner].Equal(best)
+	})
+}
+
+type mediaRange struct {
+	typ    string
+	sub    string
+	params [][2]string
+	q      float64
+}
+
+// Valid reports whether the media range has both the type and subtype.
+func (m mediaRange) Valid() bool {
+	return m.typ != "" && m.sub != ""
+}
+
+func (m mediaRange) String() string {
+	var b strings.Builder
+	b.WriteString(m.typ)
+	b.WriteByte('/')
+	b.WriteString(m.sub)
+	for _, param := range m.params {
+		b.WriteByte(';')
+		b.WriteString(param[0])
+		b.WriteByte('=')
+		b.WriteString(param[1])
+	}
+	return b.String()
+}
+
+func (m mediaRange) Equal(o mediaRange) bool {
+	return m.String() == o.String()
+}
+
+// Specificity returns the specificity of the media range,
+// which is greater if it is more specific.
+func (m mediaRange) Specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.sub == "*":
+		return 1
+	default:
+		return 2 + len(m.params)
+	}
+}
+
+// Contains reports whether the media range contains the media type.
+func (m mediaRange) Contains(t mediaRange) bool {
+	if m.typ != "*" && m.typ != t.typ {
+		return false
+	}
+	if m.sub != "*" && m.sub != t.sub {
+		return false
+	}
+
+	for _, param := range m.params {
+		var found bool
+		for _, tparam := range t.params {
+			if tparam[0] == param[0] {
+				found = strings.EqualFold(tparam[1], param[1])
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mediaRangeStrings(ranges []mediaRange) []string {
+	ss := make([]string, len(ranges))
+	for i, r := range ranges {
+		ss[i] = r.String()
+	}
+	return ss
+}
+
+func containsMediaRange(ranges []mediaRange, r mediaRange) bool {
+	for _, _r := range ranges {
+		if _r.Equal(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptQuality returns the weight of the offer by the most specific
+// media range that contains it.
+//
+// If ranges is empty, return 1.
+func acceptQuality(ranges []mediaRange, offer mediaRange) (q float64) {
+	if len(ranges) == 0 {
+		return 1
+	}
+
+	specificity := -1
+	for _, r := range ranges {
+		if s := r.Specificity(); s > specificity && r.Contains(offer) {
+			specificity, q = s, r.q
+		}
+	}
+	return
+}
+
+func parseAccept(values []string) (ranges []mediaRange) {
+	for _, token := range splitTokens(values) {
+		if r := parseMediaRange(token); r.Valid() {
+			ranges = append(ranges, r)
+		}
+	}
+	return
+}
+
+func parseMediaRange(s string) (r mediaRange) {
+	r.q = 1
+
+	mtype, params := splitParams(s)
+	if mtype = strings.ToLower(mtype); mtype == "*" {
+		mtype = "*/*"
+	}
+
+	var ok bool
+	if r.typ, r.sub, ok = strings.Cut(mtype, "/"); !ok || (r.typ == "*" && r.sub != "*") {
+		return mediaRange{}
+	}
+
+	for i, param := range params {
+		if param[0] == "q" {
+			if q, err := strconv.ParseFloat(param[1], 64); err == nil && q >= 0 && q <= 1 {
+				r.q = q
+			}
+
+			// The parameters after the weight are the accept extensions.
+			r.params = params[:i]
+			return
+		}
+	}
+
+	r.params = params
+	return
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAccepts(t *testing.T) {
+	if m := Accepts(); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+	if m := Accepts("json", "text/", "/json"); m != nil {
+		t.Errorf("expect nil for the invalid types, but got matcher '%s'", m.String())
+	}
+	if m := Accepts("json", "application/json"); m.String() != "Accepts(`application/json`)" {
+		t.Errorf("unexpected description '%s'", m.String())
+	}
+
+	req := &http.Request{Header: make(http.Header)}
+	if m := Accepts("application/json"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	req.Header.Set("Accept", "text/html;q=0.9, application/json, image/*;q=0.5, image/png;q=0, */*;q=0.1")
+	for _, _type := range []string{"text/html", "Application/JSON", "image/jpeg", "video/mp4"} {
+		if m := Accepts(_type); !m.Match(req) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		}
+	}
+
+	if m := Accepts("image/png"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.Header.Set("Accept", "text/plain;charset=utf-8")
+	if m := Accepts("text/plain;charset=UTF-8"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := Accepts("text/plain"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
+
+func TestAcceptsBest(t *testing.T) {
+	if m := AcceptsBest(""); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+	if m := AcceptsBest("json", "application/json"); m != nil {
+		t.Errorf("expect nil for the invalid type, but got matcher '%s'", m.String())
+	}
+	if m := AcceptsBest("application/json", "xml", "text/html"); m.String() != "AcceptsBest(`application/json`,[`text/html`,`application/json`])" {
+		t.Errorf("unexpected description '%s'", m.String())
+	}
+
+	offers := []string{"text/html", "application/json", "application/xml"}
+	req := &http.Request{Header: make(http.Header)}
+	req.Header.Set("Accept", "text/html;q=0.9, application/json, */*;q=0.1")
+
+	if m := AcceptsBest("application/json", offers...); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := AcceptsBest("text/html", offers...); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.Header.Set("Accept", "application/*")
+	if m := AcceptsBest("application/json", offers...); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := AcceptsBest("application/xml", offers...); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.Header.Set("Accept", "image/png")
+	if m := AcceptsBest("text/html", offers...); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIKeyInfo is the metadata of an API key.
+type APIKeyInfo struct {
+	ID      string    `json:"id"`
+	Owner   string    `json:"owner"`
+	Scopes  []string  `json:"scopes"`
+	Expires time.Time `json:"expires"` // The zero value means never expiring.
+}
+
+// Expired reports whether the API key has expired at the time now.
+func (i APIKeyInfo) Expired(now time.Time) bool {
+	return !i.Expires.IsZero() && !now.Before(i.Expires)
+}
+
+// APIKeyEntry is an API key entry stored as the salted hash,
+// which is generated by HashAPIKey.
+type APIKeyEntry struct {
+	APIKeyInfo
+	Hash string `json:"hash"`
+}
+
+// APIKeyStore is used to look up the metadata of the API key.
+//
+// The implementation should avoid the timing side-channels,
+// such as comparing the hashes in constant time.
+type APIKeyStore interface {
+	LookupAPIKey(key string) (info APIKeyInfo, ok bool)
+}
+
+// HashAPIKey returns the salted hash of the API key with a random salt,
+// which is formatted as "sha256$SALT$HASH" and used by APIKeyEntry.
+func HashAPIKey(key string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	sum := hashAPIKey(salt, key)
+	return "sha256$" + base64.RawStdEncoding.EncodeToString(salt) +
+		"$" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
+func hashAPIKey(salt []byte, key string) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(key))
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+type apiKeyHash struct {
+	info APIKeyInfo
+	salt []byte
+	hash []byte
+}
+
+func parseAPIKeyEntries(entries []APIKeyEntry) ([]apiKeyHash, error) {
+	hashes := make([]apiKeyHash, len(entries))
+	for i, entry := range entries {
+		algo, rest, _ := strings.Cut(entry.Hash, "$")
+		salt, hash, ok := strings.Cut(rest, "$")
+		if algo != "sha256" || !ok {
+			return nil, fmt.Errorf("invalid hash of the api key '%s'", entry.ID)
+		}
+
+		var err error
+		hashes[i].info = entry.APIKeyInfo
+		if hashes[i].salt, err = base64.RawStdEncoding.DecodeString(salt); err != nil {
+			return nil, fmt.Errorf("invalid salt of the api key '%s': %w", entry.ID, err)
+		}
+		if hashes[i].hash, err = base64.RawStdEncoding.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("invalid hash of the api key '%s': %w", entry.ID, err)
+		} else if len(hashes[i].hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid hash length of the api key '%s'", entry.ID)
+		}
+	}
+	return hashes, nil
+}
+
+// lookupAPIKey compares the key with all the hashes without the early return,
+// so that the lookup time does not depend on which entry matches.
+func lookupAPIKey(hashes []apiKeyHash, key string) (info APIKeyInfo, ok bool) {
+	found := -1
+	for i := range hashes {
+		sum := hashAPIKey(hashes[i].salt, key)
+		if subtle.ConstantTimeCompare(sum[:], hashes[i].hash) == 1 {
+			found = i
+		}
+	}
+
+	if found >= 0 {
+		return hashes[found].info, true
+	}
+	return
+}
+
+type memoryAPIKeyStore []apiKeyHash
+
+func (s memoryAPIKeyStore) LookupAPIKey(key string) (APIKeyInfo, bool) {
+	return lookupAPIKey(s, key)
+}
+
+// NewMemoryAPIKeyStore returns a new in-memory API key store
+// with the salted hashes of the API keys.
+func NewMemoryAPIKeyStore(entries ...APIKeyEntry) (APIKeyStore, error) {
+	hashes, err := parseAPIKeyEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+	return memoryAPIKeyStore(hashes), nil
+}
+
+type fileAPIKeyStore struct {
+	file *reloadFile[[]apiKeyHash]
+}
+
+func (s fileAPIKeyStore) LookupAPIKey(key string) (APIKeyInfo, bool) {
+	return lookupAPIKey(s.file.Get(), key)
+}
+
+// NewFileAPIKeyStore returns a new API key store backed by the local json
+// file, which contains an array of APIKeyEntry, for example,
+//
+//	[
+//	  {
+//	    "id": "partner-a",
+//	    "owner": "Partner A",
+//	    "scopes": ["orders:read"],
+//	    "expires": "2030-01-01T00:00:00Z",
+//	    "hash": "sha256$SALT$HASH"
+//	  }
+//	]
+//
+// The file is reloaded when it has changed, which is checked at most
+// once per FileCheckInterval.
+func NewFileAPIKeyStore(file string) (APIKeyStore, error) {
+	f, err := newReloadFile(file, func(data []byte) ([]apiKeyHash, error) {
+		var entries []APIKeyEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid api key file: %w", err)
+		}
+		return parseAPIKeyEntries(entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fileAPIKeyStore{file: f}, nil
+}
+
+// APIKeyOptions is the options of the APIKey matcher.
+type APIKeyOptions struct {
+	// Header is the header carrying the API key.
+	//
+	// Default: "X-API-Key" if both Header and Query are empty.
+	Header string
+
+	// Query is the query argument carrying the API key,
+	// which is used only if the header is missing.
+	Query string
+
+	// If not empty, the API key must have all the scopes.
+	Scopes []string
+}
+
+// apiKeyCacheKey is allocated per matcher, since the store may be
+// not comparable.
+type apiKeyCacheKey struct{ _ byte }
+
+type apiKeyInfoCacheKey struct{}
+
+type apiKeyResult struct {
+	info APIKeyInfo
+	ok   bool
+}
+
+// GetAPIKeyInfo returns the metadata of the API key matched by the APIKey
+// matcher, which is only available if the request is returned by WithCache.
+func GetAPIKeyInfo(r *http.Request) (info APIKeyInfo, ok bool) {
+	return lookupCache[APIKeyInfo](r, apiKeyInfoCacheKey{})
+}
+
+// APIKey returns a new matcher that checks whether the request has a valid
+// API key, which is looked up from the store and must not have expired.
+//
+// On match, the metadata of the API key is placed into the request-scoped
+// store if the request is returned by WithCache, which can be got by
+// GetAPIKeyInfo.
+//
+// If store is nil, return nil instead of an error.
+func APIKey(store APIKeyStore, opts APIKeyOptions) Matcher {
+	if store == nil {
+		return nil
+	}
+
+	if opts.Header == "" && opts.Query == "" {
+		opts.Header = "X-API-Key"
+	}
+	opts.Header = http.CanonicalHeaderKey(opts.Header)
+	opts.Scopes = append([]string(nil), opts.Scopes...)
+	key := new(apiKeyCacheKey)
+
+	desc, canon := apiKeyDesc(opts.Header, opts.Query, opts.Scopes)
+	return newc(PriorityAuth, desc, canon, func(r *http.Request) bool {
+		result := loadCache(r, key, func() apiKeyResult {
+			var apikey string
+			if opts.Header != "" {
+				apikey = r.Header.Get(opts.Header)
+			}
+			if apikey == "" && opts.Query != "" {
+				if query, ok := getQuery(r); ok {
+					apikey = query.Get(opts.Query)
+				}
+			}
+			if apikey == "" {
+				return apiKeyResult{}
+			}
+
+			info, ok := store.LookupAPIKey(apikey)
+			return apiKeyResult{info: info, ok: ok && !info.Expired(time.Now())}
+		})
+
+		if !result.ok {
+			return false
+		}
+		for _, scope := range opts.Scopes {
+			if !contains(result.info.Scopes, scope) {
+				return false
+			}
+		}
+
+		storeCache(r, apiKeyInfoCacheKey{}, result.info)
+		return true
+	})
+}
+
+func apiKeyDesc(header, query string, scopes []string) (desc, canon string) {
+	var args []string
+	if header != "" {
+		args = append(args, optdesc("header", header))
+	}
+	if query != "" {
+		args = append(args, optdesc("query", query))
+	}
+
+	format := func(scopes []string) string {
+		_args := args[:len(args):len(args)]
+		for _, scope := range scopes {
+			_args = append(_args, optdesc("scope", scope))
+		}
+		return "APIKey(" + strings.Join(_args, ",") + ")"
+	}
+
+	return format(scopes), format(sortedset(scopes))
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKey(t *testing.T) {
+	if m := APIKey(nil, APIKeyOptions{}); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	if _, err := NewMemoryAPIKeyStore(APIKeyEntry{Hash: "md5$a$b"}); err == nil {
+		t.Errorf("expect an error for the invalid hash, but got nil")
+	}
+
+	hash1, _ := HashAPIKey("key1")
+	hash2, _ := HashAPIKey("key2")
+	if strings.Contains(hash1, "key1") {
+		t.Errorf("the hash contains the plain key: %s", hash1)
+	}
+
+	entries := []APIKeyEntry{
+		{APIKeyInfo: APIKeyInfo{ID: "a", Owner: "Partner A", Scopes: []string{"orders:read", "orders:write"}}, Hash: hash1},
+		{APIKeyInfo: APIKeyInfo{ID: "b", Owner: "Partner B", Expires: time.Now().Add(-time.Hour)}, Hash: hash2},
+	}
+
+	data, _ := json.Marshal(entries)
+	file := filepath.Join(t.TempDir(), "apikeys.json")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	memstore, err := NewMemoryAPIKeyStore(entries...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filestore, err := NewFileAPIKeyStore(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newreq := func(header, query string) *http.Request {
+		req := &http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: query}}
+		if header != "" {
+			req.Header.Set("X-Api-Key", header)
+		}
+		return WithCache(req)
+	}
+
+	for _, store := range []APIKeyStore{memstore, filestore} {
+		m := APIKey(store, APIKeyOptions{})
+		if desc := m.String(); desc != "APIKey(header=`X-Api-Key`)" {
+			t.Errorf("unexpected description '%s'", desc)
+		}
+
+		req := newreq("key1", "")
+		if !m.Match(req) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		} else if info, ok := GetAPIKeyInfo(req); !ok || info.ID != "a" || info.Owner != "Partner A" {
+			t.Errorf("unexpected api key info: %v", info)
+		}
+
+		if req := newreq("key2", ""); m.Match(req) {
+			t.Errorf("unexpect match the expired key, but got matched")
+		} else if _, ok := GetAPIKeyInfo(req); ok {
+			t.Errorf("unexpect the api key info for the expired key")
+		}
+
+		if m.Match(newreq("key3", "")) || m.Match(newreq("", "api_key=key1")) {
+			t.Errorf("unexpect match '%s', but got matched", m.String())
+		}
+	}
+
+	m := APIKey(memstore, APIKeyOptions{Header: "x-api-key", Query: "api_key", Scopes: []string{"orders:write"}})
+	if desc := m.String(); desc != "APIKey(header=`X-Api-Key`,query=`api_key`,scope=`orders:write`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if !m.Match(newreq("", "api_key=key1")) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	m = APIKey(memstore, APIKeyOptions{Query: "api_key", Scopes: []string{"orders:delete"}})
+	if req := newreq("", "api_key=key1"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	} else if _, ok := GetAPIKeyInfo(req); ok {
+		t.Errorf("unexpect the api key info without the required scope")
+	}
+}
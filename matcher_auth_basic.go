@@ -0,0 +1,214 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyBcryptHash is used to verify the password of the unknown user
+// to take the similar time as the known user.
+var dummyBcryptHash = []byte("$2a$10$JuBPQtKgFxA59t4lvU4tUOzIIB4tWIofUqX4stHSSSBc4IlKcBVIS")
+
+type basicAuthCacheKey struct {
+	file *reloadFile[map[string]string]
+}
+
+type basicAuthResult struct {
+	user string
+	ok   bool
+}
+
+// BasicAuth returns a new matcher that checks whether the credentials
+// of the http basic authentication are valid, which are verified against
+// the local htpasswd file.
+//
+// The htpasswd file supports the bcrypt ("$2y$", "$2a$" or "$2b$"),
+// SHA1 ("{SHA}") and APR1-MD5 ("$apr1$") entries, and the entries
+// of the other formats are ignored. The file is reloaded when it has changed,
+// which is checked at most once per FileCheckInterval. The passwords
+// are compared in constant time.
+//
+// The verification result is cached if the request is returned by WithCache.
+func BasicAuth(file string) (Matcher, error) {
+	return newBasicAuth(file, "BasicAuth", nil)
+}
+
+// BasicAuthUsers is the same as BasicAuth, but also checks whether
+// the verified user is one of the specified users.
+//
+// If users is empty, return (nil, nil) instead of an error.
+func BasicAuthUsers(file string, users ...string) (Matcher, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return newBasicAuth(file, "BasicAuthUsers", users)
+}
+
+func newBasicAuth(file, name string, users []string) (Matcher, error) {
+	htpasswd, err := newReloadFile(file, parseHtpasswd)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := argsdesc(name, append([]string{file}, users...)...)
+	canon := argsdesc(name, append([]string{file}, sortedset(users)...)...)
+	return newc(PriorityAuth, desc, canon, func(r *http.Request) bool {
+		result := loadCache(r, basicAuthCacheKey{htpasswd}, func() basicAuthResult {
+			user, pass, ok := r.BasicAuth()
+			if !ok {
+				return basicAuthResult{}
+			}
+			return basicAuthResult{user: user, ok: verifyHtpasswd(htpasswd.Get(), user, pass)}
+		})
+		return result.ok && (len(users) == 0 || contains(users, result.user))
+	}), nil
+}
+
+func parseHtpasswd(data []byte) (map[string]string, error) {
+	users := make(map[string]string, 8)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok || user == "" || hash == "" {
+			return nil, fmt.Errorf("invalid htpasswd entry at line %d", lineno)
+		}
+
+		switch {
+		case strings.HasPrefix(hash, "$2y$"), strings.HasPrefix(hash, "$2a$"),
+			strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "{SHA}"),
+			strings.HasPrefix(hash, "$apr1$"):
+			users[user] = hash
+
+		default:
+			slog.Warn("ignore the unsupported htpasswd entry", "user", user, "line", lineno)
+		}
+	}
+	return users, scanner.Err()
+}
+
+func verifyHtpasswd(users map[string]string, user, pass string) bool {
+	hash, ok := users[user]
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(dummyBcryptHash, []byte(pass))
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(pass))
+		expect := "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(expect)) == 1
+
+	case strings.HasPrefix(hash, "$apr1$"):
+		salt, _, _ := strings.Cut(hash[len("$apr1$"):], "$")
+		expect := apr1MD5(pass, salt)
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(expect)) == 1
+
+	default:
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass)) == nil
+	}
+}
+
+// apr1MD5 returns the Apache APR1-MD5 crypt of the password with the salt,
+// such as "$apr1$SALT$HASH".
+func apr1MD5(password, salt string) string {
+	const magic = "$apr1$"
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+
+	pw, bsalt := []byte(password), []byte(salt)
+
+	alt := md5.New()
+	alt.Write(pw)
+	alt.Write(bsalt)
+	alt.Write(pw)
+	altsum := alt.Sum(nil)
+
+	h := md5.New()
+	h.Write(pw)
+	h.Write([]byte(magic))
+	h.Write(bsalt)
+	for i := len(pw); i > 0; i -= 16 {
+		h.Write(altsum[:min(i, 16)])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			h.Write([]byte{0})
+		} else {
+			h.Write(pw[:1])
+		}
+	}
+	sum := h.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		h := md5.New()
+		if i&1 != 0 {
+			h.Write(pw)
+		} else {
+			h.Write(sum)
+		}
+		if i%3 != 0 {
+			h.Write(bsalt)
+		}
+		if i%7 != 0 {
+			h.Write(pw)
+		}
+		if i&1 != 0 {
+			h.Write(sum)
+		} else {
+			h.Write(pw)
+		}
+		sum = h.Sum(nil)
+	}
+
+	const itoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	var b strings.Builder
+	b.Grow(len(magic) + len(salt) + 23)
+	b.WriteString(magic)
+	b.WriteString(salt)
+	b.WriteByte('$')
+
+	to64 := func(v uint32, n int) {
+		for ; n > 0; n-- {
+			b.WriteByte(itoa64[v&0x3f])
+			v >>= 6
+		}
+	}
+
+	for _, i := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		to64(uint32(sum[i[0]])<<16|uint32(sum[i[1]])<<8|uint32(sum[i[2]]), 4)
+	}
+	to64(uint32(sum[11]), 2)
+
+	return b.String()
+}
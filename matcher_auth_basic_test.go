@@ -0,0 +1,102 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBasicAuth(t *testing.T) {
+	const htpasswd = `# users
+bcrypt:$2a$05$gfpRvjFrQ1sJdhuMCUfkEOSkkckZdAq49mpwxcNPpJmMzTJiOJDiK
+sha:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=
+apr1:$apr1$saltsalt$LrttParrLPdxvgutaSXWJ0
+crypt:rqXexS6ZhobKA
+`
+
+	file := filepath.Join(t.TempDir(), "htpasswd")
+	if err := os.WriteFile(file, []byte(htpasswd), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BasicAuth(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+
+	m, err := BasicAuth(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &http.Request{Header: make(http.Header)}
+	if m.Match(req) {
+		t.Errorf("unexpect match '%s' without credentials, but got matched", m.String())
+	}
+
+	for _, user := range []string{"bcrypt", "sha", "apr1"} {
+		req.SetBasicAuth(user, "secret")
+		if !m.Match(req) {
+			t.Errorf("expect match '%s' for user '%s', but got not", m.String(), user)
+		}
+
+		req.SetBasicAuth(user, "wrong")
+		if m.Match(req) {
+			t.Errorf("unexpect match '%s' for user '%s', but got matched", m.String(), user)
+		}
+	}
+
+	for _, user := range []string{"crypt", "unknown"} {
+		req.SetBasicAuth(user, "secret")
+		if m.Match(req) {
+			t.Errorf("unexpect match '%s' for user '%s', but got matched", m.String(), user)
+		}
+	}
+
+	mu, err := BasicAuthUsers(file, "sha", "apr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.SetBasicAuth("apr1", "secret")
+	if !mu.Match(req) {
+		t.Errorf("expect match '%s', but got not", mu.String())
+	}
+
+	req.SetBasicAuth("bcrypt", "secret")
+	if mu.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", mu.String())
+	}
+
+	defer func(interval time.Duration) { FileCheckInterval = interval }(FileCheckInterval)
+	FileCheckInterval = 0
+
+	if err := os.WriteFile(file, []byte("sha:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	req.SetBasicAuth("bcrypt", "secret")
+	if m.Match(req) {
+		t.Errorf("unexpect match '%s' after reloading, but got matched", m.String())
+	}
+
+	req.SetBasicAuth("sha", "secret")
+	if !m.Match(req) {
+		t.Errorf("expect match '%s' after reloading, but got not", m.String())
+	}
+}
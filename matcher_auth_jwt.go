@@ -0,0 +1,508 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JWTOptions is the options to verify the JWT bearer token.
+type JWTOptions struct {
+	// JWKSFile is the local JWKS file, which is reloaded when it has changed.
+	JWKSFile string
+
+	// Keys is the static verification keys indexed by the key id,
+	// which may be empty to match the token without the key id.
+	//
+	// The key must be one of *rsa.PublicKey, *ecdsa.PublicKey,
+	// ed25519.PublicKey and []byte for HMAC.
+	Keys map[string]any
+
+	// If not empty, the claim "iss" must be equal to Issuer.
+	Issuer string
+
+	// If not empty, the claim "aud" must contain Audience.
+	Audience string
+
+	// Leeway is the allowed clock skew to check the claims "exp" and "nbf".
+	Leeway time.Duration
+
+	// If true, the claim "exp" is required.
+	//
+	// If false, the token without the claim "exp" never expires.
+	RequireExp bool
+}
+
+// JWTVerifier is used to verify the JWT bearer token locally
+// without any network access.
+type JWTVerifier struct {
+	opts  JWTOptions
+	keys  []jwk
+	jwks  *reloadFile[[]jwk]
+	now   func() time.Time
+	cache jwtCacheKey
+}
+
+type jwtCacheKey struct{ verifier *JWTVerifier }
+
+type jwk struct {
+	kid string
+	alg string
+	key any
+}
+
+// NewJWTVerifier returns a new JWT verifier, which supports the JWS
+// algorithms HS256/384/512, RS256/384/512, PS256/384/512, ES256/384/512
+// and EdDSA with Ed25519.
+func NewJWTVerifier(opts JWTOptions) (*JWTVerifier, error) {
+	v := &JWTVerifier{opts: opts, now: time.Now}
+	v.cache = jwtCacheKey{verifier: v}
+
+	for kid, key := range opts.Keys {
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, []byte:
+			v.keys = append(v.keys, jwk{kid: kid, key: key})
+		default:
+			return nil, fmt.Errorf("unsupported jwt key type %T for kid '%s'", key, kid)
+		}
+	}
+
+	if opts.JWKSFile != "" {
+		jwks, err := newReloadFile(opts.JWKSFile, parseJWKS)
+		if err != nil {
+			return nil, err
+		}
+		v.jwks = jwks
+	}
+
+	if len(v.keys) == 0 && v.jwks == nil {
+		return nil, errors.New("missing the jwt verification keys")
+	}
+
+	return v, nil
+}
+
+// Verify verifies the JWT token and returns its claims.
+func (v *JWTVerifier) Verify(token string) (claims map[string]any, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid jwt token format")
+	}
+
+	var header struct {
+		Alg  string   `json:"alg"`
+		Kid  string   `json:"kid"`
+		Crit []string `json:"crit"`
+	}
+	if err = decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid jwt header: %w", err)
+	} else if len(header.Crit) > 0 {
+		return nil, errors.New("unsupported jwt critical header parameters")
+	}
+
+	signature, err := decodeBase64URL(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt signature: %w", err)
+	}
+
+	keys := v.keys
+	if v.jwks != nil {
+		keys = append(keys[:len(keys):len(keys)], v.jwks.Get()...)
+	}
+
+	signed := []byte(token[:len(parts[0])+1+len(parts[1])])
+	if !verifyJWS(keys, header.Alg, header.Kid, signed, signature) {
+		return nil, errors.New("invalid jwt signature")
+	}
+
+	if err = decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid jwt claims: %w", err)
+	}
+
+	if err = v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *JWTVerifier) validateClaims(claims map[string]any) error {
+	now := v.now()
+	if exp, ok := claims["exp"]; ok {
+		t, ok := jwtTime(exp)
+		if !ok || !now.Before(t.Add(v.opts.Leeway)) {
+			return errors.New("jwt token is expired")
+		}
+	} else if v.opts.RequireExp {
+		return errors.New("jwt token has no expiration")
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		t, ok := jwtTime(nbf)
+		if !ok || now.Add(v.opts.Leeway).Before(t) {
+			return errors.New("jwt token is not valid yet")
+		}
+	}
+
+	if v.opts.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.opts.Issuer {
+			return errors.New("jwt issuer is not expected")
+		}
+	}
+
+	if v.opts.Audience != "" && !contains(jwtClaimValues(claims, "aud"), v.opts.Audience) {
+		return errors.New("jwt audience is not expected")
+	}
+
+	return nil
+}
+
+// String returns the identity of the verifier, that's, the issuer,
+// audience, JWKS file and static key ids, such as
+// "iss=`https://issuer.example.com`,aud=`orders`,kid=`hs`".
+func (v *JWTVerifier) String() string {
+	var args []string
+	if v.opts.Issuer != "" {
+		args = append(args, optdesc("iss", v.opts.Issuer))
+	}
+	if v.opts.Audience != "" {
+		args = append(args, optdesc("aud", v.opts.Audience))
+	}
+	if v.opts.JWKSFile != "" {
+		args = append(args, optdesc("jwks", v.opts.JWKSFile))
+	}
+
+	kids := make([]string, 0, len(v.keys))
+	for _, key := range v.keys {
+		if key.kid != "" {
+			kids = append(kids, key.kid)
+		}
+	}
+	for _, kid := range sortedset(kids) {
+		args = append(args, optdesc("kid", kid))
+	}
+	return strings.Join(args, ",")
+}
+
+// claims returns the claims of the verified bearer token of the request,
+// which is cached if the request is returned by WithCache.
+func (v *JWTVerifier) claims(r *http.Request) map[string]any {
+	return loadCache(r, v.cache, func() map[string]any {
+		auth := r.Header.Get("Authorization")
+		if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+			return nil
+		}
+
+		claims, _ := v.Verify(strings.TrimSpace(auth[7:]))
+		return claims
+	})
+}
+
+// JWT returns a new matcher that checks whether the request has a valid
+// JWT bearer token in the header "Authorization", which is verified
+// by the verifier.
+//
+// The description contains the identity of the verifier,
+// such as "JWT(iss=`https://issuer.example.com`,aud=`orders`)".
+func JWT(verifier *JWTVerifier) Matcher {
+	desc := "JWT(" + verifier.String() + ")"
+	return New(PriorityAuth, desc, func(r *http.Request) bool {
+		return verifier.claims(r) != nil
+	})
+}
+
+// JWTClaim returns a new matcher that checks whether the request has a valid
+// JWT bearer token, and the values of the claim match the value matcher
+// by the mode.
+//
+// The claim supports the dotted path to the nested claim, such as
+// "realm_access.roles". The string claim is a single value, but the claims
+// "scope" and "scp" are split by the whitespaces. The array claim has
+// multiple values, so AnyValue checks whether the array contains the value.
+// The number and bool claims are matched by their json representations.
+//
+// The description contains the identity of the verifier after the value
+// matcher, such as "JWT(`scope`,`orders:write`,iss=`https://issuer.example.com`)".
+//
+// If verifier is nil, claim is empty or vm is nil, return nil instead
+// of an error.
+func JWTClaim(verifier *JWTVerifier, claim string, mode ValuesMode, vm ValueMatcher) Matcher {
+	if verifier == nil || claim == "" || vm == nil {
+		return nil
+	}
+
+	desc := vmdesc("JWT", claim, mode, vm)
+	if id := verifier.String(); id != "" {
+		desc = desc[:len(desc)-1] + "," + id + ")"
+	}
+	return New(PriorityAuth, desc, func(r *http.Request) bool {
+		claims := verifier.claims(r)
+		return claims != nil && mode.Match(jwtClaimValues(claims, claim), vm)
+	})
+}
+
+func jwtClaimValues(claims map[string]any, claim string) (values []string) {
+	var value any = claims
+	for _, name := range strings.Split(claim, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if value, ok = m[name]; !ok {
+			return nil
+		}
+	}
+
+	switch v := value.(type) {
+	case []any:
+		values = make([]string, len(v))
+		for i, e := range v {
+			values[i] = jsonString(e)
+		}
+
+	case string:
+		if claim == "scope" || claim == "scp" {
+			values = strings.Fields(v)
+		} else {
+			values = []string{v}
+		}
+
+	default:
+		values = []string{jsonString(v)}
+	}
+
+	return
+}
+
+func jwtTime(v any) (time.Time, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	sec := int64(f)
+	return time.Unix(sec, int64((f-float64(sec))*1e9)), true
+}
+
+func decodeJWTPart(s string, v any) error {
+	data, err := decodeBase64URL(s)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func verifyJWS(keys []jwk, alg, kid string, signed, signature []byte) bool {
+	for _, key := range keys {
+		if (kid != "" && key.kid != "" && key.kid != kid) || (key.alg != "" && key.alg != alg) {
+			continue
+		}
+		if verifyJWSKey(key.key, alg, signed, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyJWSKey(key any, alg string, signed, signature []byte) bool {
+	var hash crypto.Hash
+	switch {
+	case alg == "EdDSA":
+		k, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(k, signed, signature)
+
+	case len(alg) != 5:
+		return false
+
+	case strings.HasSuffix(alg, "256"):
+		hash = crypto.SHA256
+
+	case strings.HasSuffix(alg, "384"):
+		hash = crypto.SHA384
+
+	case strings.HasSuffix(alg, "512"):
+		hash = crypto.SHA512
+
+	default:
+		return false
+	}
+
+	if alg[:2] == "HS" {
+		k, ok := key.([]byte)
+		if !ok {
+			return false
+		}
+
+		mac := hmac.New(hash.New, k)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), signature)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		k, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(k, hash, digest, signature) == nil
+
+	case "PS":
+		k, ok := key.(*rsa.PublicKey)
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+		return ok && rsa.VerifyPSS(k, hash, digest, signature, opts) == nil
+
+	case "ES":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size || size != jwsCurveSize(alg) {
+			return false
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(k, digest, r, s)
+
+	default:
+		return false
+	}
+}
+
+func jwsCurveSize(alg string) int {
+	switch alg {
+	case "ES256":
+		return 32
+	case "ES384":
+		return 48
+	case "ES512":
+		return 66
+	default:
+		return 0
+	}
+}
+
+func parseJWKS(data []byte) (keys []jwk, err error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+
+	if err = json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	for i, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var key any
+		switch k.Kty {
+		case "RSA":
+			n, nerr := decodeBase64URL(k.N)
+			e, eerr := decodeBase64URL(k.E)
+			if nerr != nil || eerr != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("invalid RSA jwk #%d", i)
+			}
+			key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				return nil, fmt.Errorf("unsupported EC curve '%s' of jwk #%d", k.Crv, i)
+			}
+
+			x, xerr := decodeBase64URL(k.X)
+			y, yerr := decodeBase64URL(k.Y)
+			if xerr != nil || yerr != nil {
+				return nil, fmt.Errorf("invalid EC jwk #%d", i)
+			}
+
+			pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			if _, err := pub.ECDH(); err != nil {
+				return nil, fmt.Errorf("invalid EC jwk #%d: %w", i, err)
+			}
+			key = pub
+
+		case "OKP":
+			x, err := decodeBase64URL(k.X)
+			if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid OKP jwk #%d", i)
+			}
+			key = ed25519.PublicKey(x)
+
+		case "oct":
+			secret, err := decodeBase64URL(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("invalid oct jwk #%d", i)
+			}
+			key = secret
+
+		default:
+			continue
+		}
+
+		keys = append(keys, jwk{kid: k.Kid, alg: k.Alg, key: key})
+	}
+
+	return
+}
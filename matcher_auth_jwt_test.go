@@ -0,0 +1,218 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func signTestJWT(t *testing.T, alg, kid string, key any, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	var err error
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+
+	case *rsa.PrivateKey:
+		if alg == "PS256" {
+			opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+			sig, err = rsa.SignPSS(rand.Reader, k, crypto.SHA256, digest[:], opts)
+		} else {
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		}
+
+	case *ecdsa.PrivateKey:
+		r, s, e := ecdsa.Sign(rand.Reader, k, digest[:])
+		sig, err = make([]byte, 64), e
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(signed))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWT(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	hsKey := []byte("secret")
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa","n":"%s","e":"AQAB"},
+		{"kty":"EC","kid":"ec","crv":"P-256","x":"%s","y":"%s"},
+		{"kty":"OKP","kid":"ed","crv":"Ed25519","x":"%s"},
+		{"kty":"RSA","kid":"enc","use":"enc","n":"%s","e":"AQAB"}
+	]}`, b64(rsaKey.N.Bytes()), b64(ecKey.X.FillBytes(make([]byte, 32))),
+		b64(ecKey.Y.FillBytes(make([]byte, 32))), b64(edPub), b64(rsaKey.N.Bytes()))
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, []byte(jwks), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewJWTVerifier(JWTOptions{}); err == nil {
+		t.Errorf("expect an error for the missing keys, but got nil")
+	}
+	if _, err := NewJWTVerifier(JWTOptions{Keys: map[string]any{"": "key"}}); err == nil {
+		t.Errorf("expect an error for the invalid key, but got nil")
+	}
+
+	verifier, err := NewJWTVerifier(JWTOptions{
+		JWKSFile: file,
+		Keys:     map[string]any{"hs": hsKey},
+		Issuer:   "https://issuer.example.com",
+		Audience: "orders",
+		Leeway:   time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Unix()
+	claims := map[string]any{
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"orders", "users"},
+		"exp":   now + 60,
+		"nbf":   now + 30,
+		"sub":   "alice",
+		"scope": "orders:read orders:write",
+		"roles": []string{"admin", "dev"},
+		"realm": map[string]any{"level": 3},
+	}
+
+	newreq := func(token string) *http.Request {
+		req := &http.Request{Header: http.Header{"Authorization": []string{"Bearer " + token}}}
+		return WithCache(req)
+	}
+
+	id := "iss=`https://issuer.example.com`,aud=`orders`,jwks=`" + file + "`,kid=`hs`"
+	valid := JWT(verifier)
+	if desc := valid.String(); desc != "JWT("+id+")" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	for _, token := range []string{
+		signTestJWT(t, "RS256", "rsa", rsaKey, claims),
+		signTestJWT(t, "PS256", "rsa", rsaKey, claims),
+		signTestJWT(t, "ES256", "ec", ecKey, claims),
+		signTestJWT(t, "EdDSA", "ed", edKey, claims),
+		signTestJWT(t, "HS256", "hs", hsKey, claims),
+		signTestJWT(t, "HS256", "", hsKey, claims),
+	} {
+		if req := newreq(token); !valid.Match(req) {
+			t.Errorf("expect the token '%s' is valid, but got not", token)
+		}
+	}
+
+	noexp := map[string]any{"iss": claims["iss"], "aud": "orders"}
+	if !valid.Match(newreq(signTestJWT(t, "HS256", "hs", hsKey, noexp))) {
+		t.Errorf("expect the token without exp is valid, but got not")
+	}
+
+	strict, err := NewJWTVerifier(JWTOptions{Keys: map[string]any{"hs": hsKey}, RequireExp: true})
+	if err != nil {
+		t.Fatal(err)
+	} else if m := JWT(strict); m.Match(newreq(signTestJWT(t, "HS256", "hs", hsKey, noexp))) {
+		t.Errorf("expect the token without exp is invalid, but got valid")
+	} else if !m.Match(newreq(signTestJWT(t, "HS256", "hs", hsKey, map[string]any{"exp": now + 60}))) {
+		t.Errorf("expect the token with exp is valid, but got not")
+	} else if desc := m.String(); desc != "JWT(kid=`hs`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	expired := map[string]any{"iss": claims["iss"], "aud": "orders", "exp": now - 120}
+	notyet := map[string]any{"iss": claims["iss"], "aud": "orders", "nbf": now + 120}
+	badiss := map[string]any{"iss": "https://other.example.com", "aud": "orders"}
+	badaud := map[string]any{"iss": claims["iss"], "aud": "users"}
+	for _, token := range []string{
+		"",
+		"a.b.c",
+		signTestJWT(t, "RS256", "ec", rsaKey, claims),
+		signTestJWT(t, "RS256", "enc", rsaKey, claims),
+		signTestJWT(t, "HS256", "hs", []byte("other"), claims),
+		"eyJhbGciOiJub25lIn0.e30.",
+		signTestJWT(t, "HS256", "hs", hsKey, expired),
+		signTestJWT(t, "HS256", "hs", hsKey, notyet),
+		signTestJWT(t, "HS256", "hs", hsKey, badiss),
+		signTestJWT(t, "HS256", "hs", hsKey, badaud),
+	} {
+		if req := newreq(token); valid.Match(req) {
+			t.Errorf("expect the token '%s' is invalid, but got valid", token)
+		}
+	}
+
+	if m := JWTClaim(verifier, "", AnyValue, Equal("a")); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := newreq(signTestJWT(t, "ES256", "ec", ecKey, claims))
+	for _, m := range []Matcher{
+		JWTClaim(verifier, "scope", AnyValue, Equal("orders:write")),
+		JWTClaim(verifier, "roles", AnyValue, Equal("admin")),
+		JWTClaim(verifier, "roles", AllValues, Glob("*")),
+		JWTClaim(verifier, "sub", AnyValue, Glob("al*")),
+		JWTClaim(verifier, "realm.level", AnyValue, Equal("3")),
+	} {
+		if !m.Match(req) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		}
+	}
+
+	for _, m := range []Matcher{
+		JWTClaim(verifier, "scope", AnyValue, Equal("orders:delete")),
+		JWTClaim(verifier, "roles", AllValues, Equal("admin")),
+		JWTClaim(verifier, "missing", AnyValue, Glob("*")),
+		JWTClaim(verifier, "realm.level.x", AnyValue, Glob("*")),
+	} {
+		if m.Match(req) {
+			t.Errorf("unexpect match '%s', but got matched", m.String())
+		}
+	}
+
+	if desc := JWTClaim(verifier, "scope", AnyValue, Equal("orders:write")).String(); desc != "JWT(`scope`,`orders:write`,"+id+")" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignedURLOptions is the options of the SignedURL matcher and SignURL.
+type SignedURLOptions struct {
+	// Keys is the HMAC-SHA256 secrets indexed by the key id,
+	// which is carried by the query argument KeyIDParam,
+	// so that the key can be rotated by adding a new key id.
+	Keys map[string]string
+
+	// Params is the query arguments covered by the signature.
+	//
+	// If empty, all the query arguments are covered except the signature.
+	Params []string
+
+	// SignatureParam is the query argument of the signature.
+	//
+	// Default: "sig"
+	SignatureParam string
+
+	// KeyIDParam is the query argument of the key id.
+	//
+	// Default: "kid"
+	KeyIDParam string
+
+	// ExpiresParam is the query argument of the unix expiry timestamp.
+	//
+	// Default: "expires"
+	ExpiresParam string
+}
+
+func (o *SignedURLOptions) init() error {
+	if len(o.Keys) == 0 {
+		return errors.New("missing the signed url keys")
+	}
+
+	if o.SignatureParam == "" {
+		o.SignatureParam = "sig"
+	}
+	if o.KeyIDParam == "" {
+		o.KeyIDParam = "kid"
+	}
+	if o.ExpiresParam == "" {
+		o.ExpiresParam = "expires"
+	}
+	o.Params = sortedset(o.Params)
+	return nil
+}
+
+// sign returns the base64url-encoded HMAC-SHA256 signature
+// over the canonical form of the request, that's,
+//
+//	METHOD "\n" PATH "\n" EXPIRES "\n" KEYID "\n" SORTED_QUERY
+//
+// where SORTED_QUERY is the covered query arguments sorted by the key,
+// which are url-encoded and joined by "&".
+func (o *SignedURLOptions) sign(secret, method, path string, query url.Values) string {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(method))
+	b.WriteByte('\n')
+	b.WriteString(path)
+	b.WriteByte('\n')
+	b.WriteString(query.Get(o.ExpiresParam))
+	b.WriteByte('\n')
+	b.WriteString(query.Get(o.KeyIDParam))
+	b.WriteByte('\n')
+
+	covered := o.Params
+	if len(covered) == 0 {
+		covered = make([]string, 0, len(query))
+		for key := range query {
+			if key != o.SignatureParam && key != o.ExpiresParam && key != o.KeyIDParam {
+				covered = append(covered, key)
+			}
+		}
+		sort.Strings(covered)
+	}
+
+	var n int
+	for _, key := range covered {
+		for _, value := range query[key] {
+			if n++; n > 1 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(value))
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(b.String()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignURL signs the url for the method with the key by the key id,
+// which sets the query arguments of the expiry, the key id and the signature.
+//
+// The path to be signed is extracted by GetPath, the same as SignedURL.
+func SignURL(opts SignedURLOptions, method string, u *url.URL, keyID string, expires time.Time) error {
+	if err := opts.init(); err != nil {
+		return err
+	}
+
+	secret, ok := opts.Keys[keyID]
+	if !ok {
+		return fmt.Errorf("no signed url key '%s'", keyID)
+	}
+
+	query := u.Query()
+	query.Del(opts.SignatureParam)
+	query.Set(opts.ExpiresParam, strconv.FormatInt(expires.Unix(), 10))
+	query.Set(opts.KeyIDParam, keyID)
+
+	path := GetPath(&http.Request{Method: method, URL: u})
+	query.Set(opts.SignatureParam, opts.sign(secret, method, path, query))
+	u.RawQuery = query.Encode()
+	return nil
+}
+
+// SignedURL returns a new matcher that checks whether the request url
+// is signed by SignURL with any of the keys, and has not expired.
+//
+// The signature covers the method, the path extracted by GetPath,
+// the expiry, the key id and the covered query arguments, so the tampered
+// link does not match. The signatures are compared in constant time.
+func SignedURL(opts SignedURLOptions) (Matcher, error) {
+	if err := opts.init(); err != nil {
+		return nil, err
+	}
+
+	kids := make([]string, 0, len(opts.Keys))
+	for kid := range opts.Keys {
+		kids = append(kids, kid)
+	}
+
+	desc := argsdesc("SignedURL", sortedset(kids)...)
+	return New(PriorityAuth, desc, func(r *http.Request) bool {
+		query, ok := getQuery(r)
+		if !ok {
+			return false
+		}
+
+		sigs, kids, exps := query[opts.SignatureParam], query[opts.KeyIDParam], query[opts.ExpiresParam]
+		if len(sigs) != 1 || len(kids) != 1 || len(exps) != 1 {
+			return false
+		}
+
+		secret, ok := opts.Keys[kids[0]]
+		if !ok {
+			return false
+		}
+
+		expires, err := strconv.ParseInt(exps[0], 10, 64)
+		if err != nil || !time.Now().Before(time.Unix(expires, 0)) {
+			return false
+		}
+
+		expect := opts.sign(secret, r.Method, GetPath(r), query)
+		return hmac.Equal([]byte(sigs[0]), []byte(expect))
+	}), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignedURL(t *testing.T) {
+	if m, err := SignedURL(SignedURLOptions{}); err == nil {
+		t.Errorf("expect an error, but got matcher '%s'", m.String())
+	}
+
+	opts := SignedURLOptions{Keys: map[string]string{"k2": "new", "k1": "old"}}
+	m, err := SignedURL(opts)
+	if err != nil {
+		t.Fatal(err)
+	} else if desc := m.String(); desc != "SignedURL(`k1`,`k2`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	sign := func(method, rawurl, kid string, expires time.Time) *url.URL {
+		u, _ := url.Parse(rawurl)
+		if err := SignURL(opts, method, u, kid, expires); err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+
+	newreq := func(method string, u *url.URL) *http.Request {
+		return &http.Request{Method: method, URL: u}
+	}
+
+	later := time.Now().Add(time.Hour)
+	for _, kid := range []string{"k1", "k2"} {
+		u := sign(http.MethodGet, "/files/report.pdf?download=1", kid, later)
+		if !m.Match(newreq(http.MethodGet, u)) {
+			t.Errorf("expect match the url '%s', but got not", u)
+		}
+	}
+
+	u := sign(http.MethodGet, "/files/report.pdf?download=1", "k1", later)
+	tamper := func(f func(q url.Values)) *url.URL {
+		q := u.Query()
+		f(q)
+		return &url.URL{Path: u.Path, RawQuery: q.Encode()}
+	}
+
+	for _, req := range []*http.Request{
+		newreq(http.MethodPost, u),
+		newreq(http.MethodGet, &url.URL{Path: "/files/secret.pdf", RawQuery: u.RawQuery}),
+		newreq(http.MethodGet, tamper(func(q url.Values) { q.Set("download", "0") })),
+		newreq(http.MethodGet, tamper(func(q url.Values) { q.Add("extra", "1") })),
+		newreq(http.MethodGet, tamper(func(q url.Values) { q.Set("expires", "9999999999") })),
+		newreq(http.MethodGet, tamper(func(q url.Values) { q.Set("kid", "k2") })),
+		newreq(http.MethodGet, tamper(func(q url.Values) { q.Del("sig") })),
+		newreq(http.MethodGet, sign(http.MethodGet, "/files/report.pdf", "k1", time.Now().Add(-time.Second))),
+	} {
+		if m.Match(req) {
+			t.Errorf("unexpect match the url '%s', but got matched", req.URL)
+		}
+	}
+
+	if err := SignURL(opts, http.MethodGet, &url.URL{Path: "/"}, "k3", later); err == nil {
+		t.Errorf("expect an error for the unknown key id, but got nil")
+	}
+
+	opts.Params = []string{"download"}
+	if m, err = SignedURL(opts); err != nil {
+		t.Fatal(err)
+	}
+
+	u = sign(http.MethodGet, "/files/report.pdf?download=1&utm_source=mail", "k1", later)
+	if !m.Match(newreq(http.MethodGet, tamper(func(q url.Values) { q.Set("utm_source", "web") }))) {
+		t.Errorf("expect match the url with the uncovered argument changed, but got not")
+	}
+	if m.Match(newreq(http.MethodGet, tamper(func(q url.Values) { q.Set("download", "0") }))) {
+		t.Errorf("unexpect match the url with the covered argument changed, but got matched")
+	}
+}
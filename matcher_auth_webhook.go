@@ -0,0 +1,284 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookOptions is the options of the WebhookSignature matcher.
+//
+// For example,
+//
+//	// GitHub
+//	WebhookOptions{
+//		Header:  "X-Hub-Signature-256",
+//		Prefix:  "sha256=",
+//		Secrets: []string{"secret"},
+//	}
+//
+//	// Slack
+//	WebhookOptions{
+//		Header:          "X-Slack-Signature",
+//		Prefix:          "v0=",
+//		TimestampHeader: "X-Slack-Request-Timestamp",
+//		Template:        "v0:{timestamp}:{body}",
+//		Tolerance:       5 * time.Minute,
+//		Secrets:         []string{"secret"},
+//	}
+//
+//	// Stripe
+//	WebhookOptions{
+//		Header:         "Stripe-Signature",
+//		SignatureParam: "v1",
+//		TimestampParam: "t",
+//		Template:       "{timestamp}.{body}",
+//		Tolerance:      5 * time.Minute,
+//		Secrets:        []string{"whsec_xxx"},
+//	}
+type WebhookOptions struct {
+	// Header is the header carrying the signature, which is required.
+	Header string
+
+	// Algorithm is the hash algorithm of HMAC, such as "sha1",
+	// "sha256" or "sha512".
+	//
+	// Default: "sha256"
+	Algorithm string
+
+	// Encoding is the encoding of the signature, such as "hex" or "base64".
+	//
+	// Default: "hex"
+	Encoding string
+
+	// Prefix is the prefix of the signature, such as "sha256=",
+	// which is removed before decoding the signature.
+	Prefix string
+
+	// If not empty, the signature header is a comma-separated list
+	// of the key-value pairs, such as "t=1700000000,v1=abc,v1=def",
+	// and the signatures are the values of the key SignatureParam.
+	SignatureParam string
+
+	// TimestampParam is the key of the unix timestamp in the signature header,
+	// which is only used when SignatureParam is set.
+	TimestampParam string
+
+	// TimestampHeader is the header carrying the unix timestamp.
+	TimestampHeader string
+
+	// Template is the template of the signed payload, in which
+	// "{timestamp}" is replaced with the timestamp and "{body}"
+	// is replaced with the raw request body. "{timestamp}" requires
+	// TimestampHeader, or SignatureParam and TimestampParam.
+	//
+	// Default: "{body}"
+	Template string
+
+	// Tolerance is the replay window, that's, the request whose timestamp
+	// differs from now by more than it does not match.
+	//
+	// If zero, the timestamp is not checked. Or, Template must contain
+	// "{timestamp}" so that the timestamp is signed.
+	Tolerance time.Duration
+
+	// Secrets is the active secrets, any of which may sign the request,
+	// so that the secret can be rotated without the downtime.
+	Secrets []string
+}
+
+type webhookCacheKey struct{ _ byte }
+
+// WebhookSignature returns a new matcher that checks whether the webhook
+// request is signed with HMAC by any of the secrets.
+//
+// The request body is read up to MaxBodySize and restored, and the result
+// is cached if the request is returned by WithCache. The signatures
+// are compared in constant time.
+func WebhookSignature(opts WebhookOptions) (Matcher, error) {
+	if opts.Header == "" {
+		return nil, errors.New("missing the webhook signature header")
+	}
+	if len(opts.Secrets) == 0 {
+		return nil, errors.New("missing the webhook secrets")
+	}
+
+	newHash, err := getHMACHash(opts.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	decode, err := getSignatureDecoder(opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Template == "" {
+		opts.Template = "{body}"
+	}
+
+	signTimestamp := strings.Contains(opts.Template, "{timestamp}")
+	hasTimestamp := opts.TimestampHeader != "" || (opts.SignatureParam != "" && opts.TimestampParam != "")
+	if opts.Tolerance > 0 && !signTimestamp {
+		// Otherwise, the unsigned timestamp can be replaced to replay the request.
+		return nil, errors.New("the webhook template must sign {timestamp} for the replay window")
+	}
+	if signTimestamp && !hasTimestamp {
+		return nil, errors.New("missing the webhook timestamp header or param for {timestamp}")
+	}
+	useTimestamp := hasTimestamp || signTimestamp
+
+	secrets := make([][]byte, len(opts.Secrets))
+	for i, secret := range opts.Secrets {
+		secrets[i] = []byte(secret)
+	}
+
+	key := new(webhookCacheKey)
+	desc := argsdesc("WebhookSignature", opts.Header)
+	return New(PriorityAuth, desc, func(r *http.Request) bool {
+		return loadCache(r, key, func() bool {
+			sigs, timestamp := parseWebhookSignatures(r, opts)
+			if len(sigs) == 0 || (useTimestamp && timestamp == "") {
+				return false
+			}
+
+			if opts.Tolerance > 0 {
+				ts, err := strconv.ParseInt(timestamp, 10, 64)
+				if err != nil {
+					return false
+				}
+				if d := time.Since(time.Unix(ts, 0)); d > opts.Tolerance || d < -opts.Tolerance {
+					return false
+				}
+			}
+
+			body, err := getBody(r)
+			if err != nil {
+				return false
+			}
+
+			var ok bool
+			for _, secret := range secrets {
+				mac := hmac.New(newHash, secret)
+				writeWebhookPayload(mac, opts.Template, timestamp, body)
+				sum := mac.Sum(nil)
+
+				for _, sig := range sigs {
+					if expect, err := decode(sig); err == nil && hmac.Equal(sum, expect) {
+						ok = true
+					}
+				}
+			}
+			return ok
+		})
+	}), nil
+}
+
+func parseWebhookSignatures(r *http.Request, opts WebhookOptions) (sigs []string, timestamp string) {
+	if opts.TimestampHeader != "" {
+		timestamp = strings.TrimSpace(r.Header.Get(opts.TimestampHeader))
+	}
+
+	for _, value := range r.Header.Values(opts.Header) {
+		if opts.SignatureParam == "" {
+			if sig, ok := strings.CutPrefix(strings.TrimSpace(value), opts.Prefix); ok && sig != "" {
+				sigs = append(sigs, sig)
+			}
+			continue
+		}
+
+		for _, pair := range strings.Split(value, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			switch key {
+			case opts.SignatureParam:
+				if sig, ok := strings.CutPrefix(value, opts.Prefix); ok && sig != "" {
+					sigs = append(sigs, sig)
+				}
+
+			case opts.TimestampParam:
+				if opts.TimestampParam != "" && timestamp == "" {
+					timestamp = value
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func writeWebhookPayload(w hash.Hash, template, timestamp string, body []byte) {
+	for template != "" {
+		i := strings.IndexByte(template, '{')
+		if i < 0 {
+			break
+		}
+
+		w.Write([]byte(template[:i]))
+		switch template = template[i:]; {
+		case strings.HasPrefix(template, "{timestamp}"):
+			w.Write([]byte(timestamp))
+			template = template[len("{timestamp}"):]
+
+		case strings.HasPrefix(template, "{body}"):
+			w.Write(body)
+			template = template[len("{body}"):]
+
+		default:
+			w.Write([]byte{'{'})
+			template = template[1:]
+		}
+	}
+	w.Write([]byte(template))
+}
+
+func getHMACHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported hmac algorithm '%s'", algorithm)
+	}
+}
+
+func getSignatureDecoder(encoding string) (func(string) ([]byte, error), error) {
+	switch strings.ToLower(encoding) {
+	case "", "hex":
+		return hex.DecodeString, nil
+	case "base64":
+		return base64.StdEncoding.DecodeString, nil
+	case "base64url":
+		return func(s string) ([]byte, error) {
+			return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature encoding '%s'", encoding)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func hmacSHA256(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func TestWebhookSignature(t *testing.T) {
+	for _, opts := range []WebhookOptions{
+		{Secrets: []string{"secret"}},
+		{Header: "X-Signature"},
+		{Header: "X-Signature", Secrets: []string{"secret"}, Algorithm: "md5"},
+		{Header: "X-Signature", Secrets: []string{"secret"}, Encoding: "base32"},
+		{Header: "X-Signature", Secrets: []string{"secret"}, Tolerance: time.Minute},
+		{Header: "X-Signature", Secrets: []string{"secret"}, TimestampHeader: "X-Ts", Tolerance: time.Minute},
+		{Header: "X-Signature", Secrets: []string{"secret"}, Template: "{timestamp}.{body}", Tolerance: time.Minute},
+		{Header: "X-Signature", Secrets: []string{"secret"}, Template: "{timestamp}.{body}", SignatureParam: "v1"},
+	} {
+		if m, err := WebhookSignature(opts); err == nil {
+			t.Errorf("expect an error, but got matcher '%s'", m.String())
+		}
+	}
+
+	const body = `{"action":"opened"}`
+	newreq := func(headers ...string) *http.Request {
+		req := &http.Request{Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Add(headers[i], headers[i+1])
+		}
+		return WithCache(req)
+	}
+
+	// GitHub
+	github, err := WebhookSignature(WebhookOptions{
+		Header:  "X-Hub-Signature-256",
+		Prefix:  "sha256=",
+		Secrets: []string{"new", "old"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if desc := github.String(); desc != "WebhookSignature(`X-Hub-Signature-256`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	for _, secret := range []string{"new", "old"} {
+		sig := "sha256=" + hex.EncodeToString(hmacSHA256(secret, body))
+		req := newreq("X-Hub-Signature-256", sig)
+		if !github.Match(req) {
+			t.Errorf("expect match the signature by the secret '%s', but got not", secret)
+		}
+		if data, _ := io.ReadAll(req.Body); string(data) != body {
+			t.Errorf("the body is not restored, got '%s'", data)
+		}
+	}
+
+	for _, sig := range []string{
+		"",
+		hex.EncodeToString(hmacSHA256("new", body)),
+		"sha256=" + hex.EncodeToString(hmacSHA256("other", body)),
+		"sha256=invalid",
+	} {
+		if github.Match(newreq("X-Hub-Signature-256", sig)) {
+			t.Errorf("unexpect match the signature '%s', but got matched", sig)
+		}
+	}
+
+	// Slack
+	slack, err := WebhookSignature(WebhookOptions{
+		Header:          "X-Slack-Signature",
+		Prefix:          "v0=",
+		TimestampHeader: "X-Slack-Request-Timestamp",
+		Template:        "v0:{timestamp}:{body}",
+		Tolerance:       5 * time.Minute,
+		Secrets:         []string{"secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	slacksig := func(ts string) string {
+		return "v0=" + hex.EncodeToString(hmacSHA256("secret", "v0:"+ts+":"+body))
+	}
+
+	if !slack.Match(newreq("X-Slack-Signature", slacksig(now), "X-Slack-Request-Timestamp", now)) {
+		t.Errorf("expect match the slack signature, but got not")
+	}
+	if slack.Match(newreq("X-Slack-Signature", slacksig(old), "X-Slack-Request-Timestamp", old)) {
+		t.Errorf("unexpect match the replayed slack signature, but got matched")
+	}
+	if slack.Match(newreq("X-Slack-Signature", slacksig(now))) {
+		t.Errorf("unexpect match the slack signature without timestamp, but got matched")
+	}
+
+	// Stripe
+	stripe, err := WebhookSignature(WebhookOptions{
+		Header:         "Stripe-Signature",
+		SignatureParam: "v1",
+		TimestampParam: "t",
+		Template:       "{timestamp}.{body}",
+		Tolerance:      5 * time.Minute,
+		Secrets:        []string{"whsec"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := hex.EncodeToString(hmacSHA256("whsec", now+"."+body))
+	if !stripe.Match(newreq("Stripe-Signature", "t="+now+",v1=bad,v1="+sig)) {
+		t.Errorf("expect match the stripe signature, but got not")
+	}
+	if stripe.Match(newreq("Stripe-Signature", "t="+old+",v1="+sig)) {
+		t.Errorf("unexpect match the stripe signature with the other timestamp, but got matched")
+	}
+
+	// Base64
+	b64, err := WebhookSignature(WebhookOptions{
+		Header:   "X-Signature",
+		Encoding: "base64",
+		Secrets:  []string{"secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig = base64.StdEncoding.EncodeToString(hmacSHA256("secret", body))
+	if !b64.Match(newreq("X-Signature", sig)) {
+		t.Errorf("expect match the base64 signature, but got not")
+	}
+}
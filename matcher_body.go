@@ -0,0 +1,74 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// MaxBodySize is the maximum size of the request body read by the body
+// matchers, such as BodyJSON. The request whose body is larger than it
+// does not match any body matcher.
+var MaxBodySize int64 = 1 << 20
+
+var errBodyTooLarge = errors.New("request body is too large")
+
+type bodyCacheKey struct{}
+
+type bufferedBody struct {
+	data []byte
+	err  error
+}
+
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
+
+// getBody reads the request body up to MaxBodySize and restores r.Body,
+// so that the handler can read the whole body again.
+//
+// The read body is cached if the request is returned by WithCache.
+func getBody(r *http.Request) ([]byte, error) {
+	body := loadCache(r, bodyCacheKey{}, func() bufferedBody {
+		if r.Body == nil || r.Body == http.NoBody {
+			return bufferedBody{}
+		}
+
+		limit := MaxBodySize
+		data, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		switch {
+		case err != nil:
+			r.Body = restoredBody{Reader: io.MultiReader(bytes.NewReader(data), errReader{err}), Closer: r.Body}
+
+		case int64(len(data)) > limit:
+			err = errBodyTooLarge
+			r.Body = restoredBody{Reader: io.MultiReader(bytes.NewReader(data), r.Body), Closer: r.Body}
+
+		default:
+			r.Body = restoredBody{Reader: bytes.NewReader(data), Closer: r.Body}
+		}
+
+		return bufferedBody{data: data, err: err}
+	})
+	return body.data, body.err
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
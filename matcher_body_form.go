@@ -0,0 +1,215 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+type formCacheKey struct{}
+
+type formPart struct {
+	name     string
+	filename string
+	mtype    string
+	params   map[string]string
+}
+
+type parsedForm struct {
+	values url.Values
+	parts  []formPart
+	ok     bool
+}
+
+// getForm parses the urlencoded or multipart form from the request body
+// without consuming it, which is cached if the request is returned
+// by WithCache.
+//
+// For the multipart form, the values of the non-file parts are collected
+// into values, and the headers of all the parts are collected into parts.
+func getForm(r *http.Request) parsedForm {
+	return loadCache(r, formCacheKey{}, func() (form parsedForm) {
+		mtype, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return
+		}
+
+		switch mtype {
+		case "application/x-www-form-urlencoded":
+			data, err := getBody(r)
+			if err != nil {
+				return
+			}
+
+			form.values, _ = url.ParseQuery(string(data))
+			form.ok = true
+
+		case "multipart/form-data":
+			boundary := params["boundary"]
+			if boundary == "" {
+				return
+			}
+
+			data, err := getBody(r)
+			if err != nil {
+				return
+			}
+
+			form.values = make(url.Values, 4)
+			mr := multipart.NewReader(bytes.NewReader(data), boundary)
+			for {
+				part, err := mr.NextPart()
+				if errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					return parsedForm{}
+				}
+
+				p := formPart{name: part.FormName(), filename: part.FileName()}
+				if ct := part.Header.Get("Content-Type"); ct != "" {
+					p.mtype, p.params, _ = mime.ParseMediaType(ct)
+				} else if p.filename != "" {
+					p.mtype = "application/octet-stream"
+				} else {
+					p.mtype = "text/plain"
+				}
+				form.parts = append(form.parts, p)
+
+				if p.name != "" && p.filename == "" {
+					value, err := io.ReadAll(part)
+					if err != nil {
+						return parsedForm{}
+					}
+					form.values[p.name] = append(form.values[p.name], string(value))
+				}
+			}
+			form.ok = true
+		}
+
+		return
+	})
+}
+
+// FormValue returns a new matcher that checks whether the values of the form
+// field key in the request body match the value matcher by the mode.
+//
+// It supports the form of "application/x-www-form-urlencoded",
+// and the non-file parts of "multipart/form-data". The body is read
+// up to MaxBodySize and restored, so the handler can read it again.
+//
+// If vm is nil, it only checks whether the form has the field key.
+//
+// If key is empty, return nil instead of an error.
+func FormValue(key string, mode ValuesMode, vm ValueMatcher) Matcher {
+	if key == "" {
+		return nil
+	}
+
+	var desc string
+	if vm == nil {
+		desc = fmt.Sprintf("FormValue(`%s`)", key)
+	} else {
+		desc = vmdesc("FormValue", key, mode, vm)
+	}
+
+	return New(PriorityBody, desc, func(r *http.Request) bool {
+		form := getForm(r)
+		if !form.ok {
+			return false
+		}
+
+		values, ok := form.values[key]
+		if vm == nil {
+			return ok
+		}
+		return mode.Match(values, vm)
+	})
+}
+
+// PartSpec is the specification of a part of the multipart form.
+//
+// The empty field means any.
+type PartSpec struct {
+	// Name is the form field name of the part.
+	Name string
+
+	// ContentType is the media type pattern of the part, which supports
+	// the same pattern as the matcher ContentType, such as "image/*".
+	//
+	// If the part has no the header "Content-Type", it is "text/plain"
+	// for the non-file part or "application/octet-stream" for the file part.
+	ContentType string
+
+	// Ext is the extension of the filename of the part, such as ".png",
+	// which is case-insensitive. If set, the part must be a file part.
+	Ext string
+}
+
+func (s PartSpec) String() string {
+	args := make([]string, 0, 3)
+	if s.Name != "" {
+		args = append(args, optdesc("name", s.Name))
+	}
+	if s.ContentType != "" {
+		args = append(args, optdesc("type", s.ContentType))
+	}
+	if s.Ext != "" {
+		args = append(args, optdesc("ext", s.Ext))
+	}
+	return strings.Join(args, ",")
+}
+
+// MultipartPart returns a new matcher that checks whether the multipart form
+// in the request body has a part matching the specification.
+//
+// The body is read up to MaxBodySize and restored, so the handler
+// can read it again.
+func MultipartPart(spec PartSpec) (Matcher, error) {
+	var ct contentTypePattern
+	if spec.ContentType != "" {
+		var err error
+		if ct, err = parseContentTypePattern(spec.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	ext := strings.ToLower(spec.Ext)
+	if ext != "" && ext[0] != '.' {
+		ext = "." + ext
+	}
+
+	desc := fmt.Sprintf("MultipartPart(%s)", spec.String())
+	return New(PriorityBody, desc, func(r *http.Request) bool {
+		for _, part := range getForm(r).parts {
+			switch {
+			case spec.Name != "" && part.name != spec.Name:
+			case spec.ContentType != "" && !ct.Match(part.mtype, part.params):
+			case ext != "" && (part.filename == "" || strings.ToLower(path.Ext(part.filename)) != ext):
+			default:
+				return true
+			}
+		}
+		return false
+	}), nil
+}
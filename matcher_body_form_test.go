@@ -0,0 +1,111 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func TestFormValue(t *testing.T) {
+	if m := FormValue("", AnyValue, nil); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	const body = "action=create&tag=a&tag=b"
+	req := &http.Request{Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = WithCache(req)
+
+	if m := FormValue("action", AnyValue, Equal("create")); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	} else if desc := m.String(); desc != "FormValue(`action`,`create`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m := FormValue("tag", LastValue, Equal("b")); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := FormValue("other", AnyValue, nil); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	if data, _ := io.ReadAll(req.Body); string(data) != body {
+		t.Errorf("the body is not restored, got '%s'", data)
+	}
+}
+
+func TestMultipartPart(t *testing.T) {
+	if m, err := MultipartPart(PartSpec{ContentType: "image"}); err == nil {
+		t.Errorf("expect an error, but got a matcher '%s'", m.String())
+	}
+
+	buf := bytes.NewBuffer(nil)
+	mw := multipart.NewWriter(buf)
+	_ = mw.WriteField("action", "upload")
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="avatar"; filename="Me.PNG"`)
+	header.Set("Content-Type", "image/png")
+	w, _ := mw.CreatePart(header)
+	_, _ = w.Write([]byte("fake png data"))
+	_ = mw.Close()
+	body := buf.String()
+
+	newreq := func() *http.Request {
+		req := &http.Request{Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return WithCache(req)
+	}
+
+	req := newreq()
+	if m := FormValue("action", AnyValue, Equal("upload")); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	for _, spec := range []PartSpec{
+		{Name: "avatar"},
+		{ContentType: "image/*", Ext: "png"},
+		{Name: "avatar", Ext: ".png"},
+		{Name: "action", ContentType: "text/plain"},
+	} {
+		if m, err := MultipartPart(spec); err != nil {
+			t.Error(err)
+		} else if !m.Match(req) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		}
+	}
+
+	for _, spec := range []PartSpec{
+		{Name: "file"},
+		{Name: "avatar", ContentType: "image/jpeg"},
+		{Name: "action", Ext: ".txt"},
+	} {
+		if m, err := MultipartPart(spec); err != nil {
+			t.Error(err)
+		} else if m.Match(req) {
+			t.Errorf("unexpect match '%s', but got matched", m.String())
+		}
+	}
+
+	if data, _ := io.ReadAll(req.Body); string(data) != body {
+		t.Errorf("the body is not restored")
+	}
+}
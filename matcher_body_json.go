@@ -0,0 +1,249 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type jsonCacheKey struct{}
+
+type parsedJSON struct {
+	doc any
+	ok  bool
+}
+
+// getJSON returns the parsed json document of the request body,
+// which is cached if the request is returned by WithCache.
+func getJSON(r *http.Request) (doc any, ok bool) {
+	v := loadCache(r, jsonCacheKey{}, func() parsedJSON {
+		data, err := getBody(r)
+		if err != nil || len(bytes.TrimSpace(data)) == 0 {
+			return parsedJSON{}
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			return parsedJSON{}
+		}
+
+		// Reject the trailing data after the json document.
+		if err := dec.Decode(new(any)); err != io.EOF {
+			return parsedJSON{}
+		}
+		return parsedJSON{doc: doc, ok: true}
+	})
+	return v.doc, v.ok
+}
+
+// BodyJSON returns a new matcher that checks whether the values selected
+// by the path from the json request body match the value matcher by the mode.
+//
+// The path supports the JSON Pointer of RFC 6901, such as "/event/type",
+// and the subset of JSONPath, such as "$.event.type", "$.items[0].id",
+// "$['event']['type']" and the wildcard "$.items[*].id". The selected
+// string is matched as it is, the number and bool are matched by their json
+// representations, such as "1.5" and "true", null is matched as "null",
+// and the object and array are matched by their compact json representations.
+//
+// If vm is nil, it only checks whether the path selects any value.
+//
+// The body is read up to MaxBodySize and restored on the matched request,
+// so the handler can read it again. So, if the request is returned
+// by WithCache, the returned request must be the one passed to the handler.
+// If the body is not a valid json, it does not match.
+func BodyJSON(path string, mode ValuesMode, vm ValueMatcher) (Matcher, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var desc string
+	if vm == nil {
+		desc = fmt.Sprintf("BodyJSON(`%s`)", path)
+	} else {
+		desc = vmdesc("BodyJSON", path, mode, vm)
+	}
+
+	return New(PriorityBody, desc, func(r *http.Request) bool {
+		doc, ok := getJSON(r)
+		if !ok {
+			return false
+		}
+
+		values := selectJSON(nil, doc, steps)
+		if vm == nil {
+			return len(values) > 0
+		}
+
+		svalues := make([]string, len(values))
+		for i, value := range values {
+			svalues[i] = jsonString(value)
+		}
+		return mode.Match(svalues, vm)
+	}), nil
+}
+
+type jsonStep struct {
+	key   string
+	index int // -1 means the object key, and -2 means the wildcard.
+}
+
+func selectJSON(values []any, doc any, steps []jsonStep) []any {
+	if len(steps) == 0 {
+		return append(values, doc)
+	}
+
+	step := steps[0]
+	switch v := doc.(type) {
+	case map[string]any:
+		switch step.index {
+		case -1:
+			if value, ok := v[step.key]; ok {
+				values = selectJSON(values, value, steps[1:])
+			}
+		case -2: // Sort the keys to select the values in a stable order.
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				values = selectJSON(values, v[key], steps[1:])
+			}
+		}
+
+	case []any:
+		switch {
+		case step.index == -2:
+			for _, value := range v {
+				values = selectJSON(values, value, steps[1:])
+			}
+
+		case step.index >= 0 && step.index < len(v):
+			values = selectJSON(values, v[step.index], steps[1:])
+
+		case step.index == -1: // For JSON Pointer, such as "/items/0".
+			if index, err := strconv.Atoi(step.key); err == nil && index >= 0 && index < len(v) {
+				values = selectJSON(values, v[index], steps[1:])
+			}
+		}
+	}
+
+	return values
+}
+
+func jsonString(v any) string {
+	switch _v := v.(type) {
+	case string:
+		return _v
+	case json.Number:
+		return _v.String()
+	case bool:
+		return strconv.FormatBool(_v)
+	case nil:
+		return "null"
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+func parseJSONPath(path string) (steps []jsonStep, err error) {
+	switch {
+	case path == "":
+		return nil, nil
+
+	case path[0] == '/':
+		return parseJSONPointer(path), nil
+
+	case path[0] == '$':
+		return parseJSONPathExpr(path)
+
+	default:
+		return nil, fmt.Errorf("invalid json path '%s': must start with '/' or '$'", path)
+	}
+}
+
+func parseJSONPointer(path string) (steps []jsonStep) {
+	replacer := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, token := range strings.Split(path[1:], "/") {
+		steps = append(steps, jsonStep{key: replacer.Replace(token), index: -1})
+	}
+	return
+}
+
+func parseJSONPathExpr(path string) (steps []jsonStep, err error) {
+	s := path[1:]
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+
+			key := s[:end]
+			switch key {
+			case "":
+				return nil, fmt.Errorf("invalid json path '%s': empty key", path)
+			case "*":
+				steps = append(steps, jsonStep{index: -2})
+			default:
+				steps = append(steps, jsonStep{key: key, index: -1})
+			}
+			s = s[end:]
+
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path '%s': missing ']'", path)
+			}
+
+			sel := strings.TrimSpace(s[1:end])
+			switch {
+			case sel == "*":
+				steps = append(steps, jsonStep{index: -2})
+
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				steps = append(steps, jsonStep{key: sel[1 : len(sel)-1], index: -1})
+
+			default:
+				index, err := strconv.Atoi(sel)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid json path '%s': invalid index '%s'", path, sel)
+				}
+				steps = append(steps, jsonStep{index: index})
+			}
+			s = s[end+1:]
+
+		default:
+			return nil, fmt.Errorf("invalid json path '%s'", path)
+		}
+	}
+	return
+}
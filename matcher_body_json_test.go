@@ -0,0 +1,106 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBodyJSON(t *testing.T) {
+	for _, path := range []string{"event.type", "$.", "$.items[a]", "$.items[0"} {
+		if m, err := BodyJSON(path, AnyValue, nil); err == nil {
+			t.Errorf("expect an error, but got a matcher '%s'", m.String())
+		}
+	}
+
+	const body = `{"event":{"type":"push","id":123,"ok":true},"items":[{"id":"a"},{"id":"b"}],"a/b":null}`
+	newreq := func() *http.Request {
+		return &http.Request{Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	tests := []struct {
+		path   string
+		mode   ValuesMode
+		vm     ValueMatcher
+		expect bool
+	}{
+		{"$.event.type", AnyValue, Equal("push"), true},
+		{"/event/type", AnyValue, Equal("push"), true},
+		{"$['event']['id']", AnyValue, Equal("123"), true},
+		{"$.event.ok", AnyValue, Equal("true"), true},
+		{"$.items[1].id", AnyValue, Equal("b"), true},
+		{"/items/0/id", AnyValue, Equal("a"), true},
+		{"$.items[*].id", AllValues, Glob("?"), true},
+		{"$.items[*].id", FirstValue, Equal("b"), false},
+		{"/a~1b", AnyValue, Equal("null"), true},
+		{"$.event.type", AnyValue, nil, true},
+		{"$.event.name", AnyValue, nil, false},
+		{"$.items[2].id", AnyValue, nil, false},
+	}
+
+	for _, test := range tests {
+		m, err := BodyJSON(test.path, test.mode, test.vm)
+		if err != nil {
+			t.Errorf("%s: %s", test.path, err)
+			continue
+		}
+
+		req := newreq()
+		if result := m.Match(req); result != test.expect {
+			t.Errorf("%s: expect %v, but got %v", m.String(), test.expect, result)
+		}
+
+		if data, _ := io.ReadAll(req.Body); string(data) != body {
+			t.Errorf("%s: the body is not restored, got '%s'", m.String(), data)
+		}
+	}
+
+	m, _ := BodyJSON("$.event.type", AnyValue, Equal("push"))
+	if desc := m.String(); desc != "BodyJSON(`$.event.type`,`push`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	req := &http.Request{Body: io.NopCloser(strings.NewReader(`{"event":{"type":"push"}} garbage`))}
+	if m.Match(req) {
+		t.Errorf("unexpect match '%s' for the trailing garbage, but got matched", m.String())
+	}
+
+	orig := newreq()
+	req = WithCache(orig)
+	if m := And(m, m); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	// The body is only restored on the request returned by WithCache.
+	if data, _ := io.ReadAll(req.Body); string(data) != body {
+		t.Errorf("the body of the cached request is not restored, got '%s'", data)
+	}
+	if data, _ := io.ReadAll(orig.Body); len(data) != 0 {
+		t.Errorf("expect the body of the original request is drained, but got '%s'", data)
+	}
+
+	defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 16
+
+	req = newreq()
+	if m.Match(req) {
+		t.Errorf("unexpect match '%s' for the too large body, but got matched", m.String())
+	} else if data, _ := io.ReadAll(req.Body); string(data) != body {
+		t.Errorf("the body is not restored, got '%s'", data)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type cacheKey struct{}
+
+type cache struct {
+	lock   sync.Mutex
+	values map[any]any
+}
+
+// WithCache returns a shallow copy of the request with a request-scoped
+// attribute store, which is shared by all the matchers evaluating
+// the returned request to cache the parsed results, such as the parsed
+// query, so that they are parsed only once per request.
+//
+// If the request has had the store, return itself.
+//
+// The matchers reading the body, such as BodyJSON, restore the body
+// only on the returned request, so it, not the original request,
+// must be the one passed to the handler.
+//
+// Without the store, the matchers still work, but parse the request
+// every time when matching it.
+func WithCache(r *http.Request) *http.Request {
+	if getCache(r) != nil {
+		return r
+	}
+
+	c := &cache{values: make(map[any]any, 4)}
+	return r.WithContext(context.WithValue(r.Context(), cacheKey{}, c))
+}
+
+func getCache(r *http.Request) *cache {
+	c, _ := r.Context().Value(cacheKey{}).(*cache)
+	return c
+}
+
+// loadCache returns the value cached by the key in the request-scoped store.
+// If missing, call load to get the value and cache it.
+func loadCache[T any](r *http.Request, key any, load func() T) T {
+	c := getCache(r)
+	if c == nil {
+		return load()
+	}
+
+	c.lock.Lock()
+	v, ok := c.values[key]
+	c.lock.Unlock()
+	if ok {
+		return v.(T)
+	}
+
+	value := load()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if v, ok := c.values[key]; ok { // Loaded by others concurrently.
+		return v.(T)
+	}
+	c.values[key] = value
+	return value
+}
+
+// storeCache stores the value by the key into the request-scoped store.
+// If the request has no store, do nothing.
+func storeCache(r *http.Request, key, value any) {
+	if c := getCache(r); c != nil {
+		c.lock.Lock()
+		c.values[key] = value
+		c.lock.Unlock()
+	}
+}
+
+// lookupCache returns the value cached by the key in the request-scoped store.
+func lookupCache[T any](r *http.Request, key any) (value T, ok bool) {
+	if c := getCache(r); c != nil {
+		c.lock.Lock()
+		value, ok = c.values[key].(T)
+		c.lock.Unlock()
+	}
+	return
+}
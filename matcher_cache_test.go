@@ -0,0 +1,49 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithCache(t *testing.T) {
+	type key struct{}
+
+	var loads int
+	load := func() int { loads++; return loads }
+
+	req := &http.Request{}
+	loadCache(req, key{}, load)
+	loadCache(req, key{}, load)
+	if loads != 2 {
+		t.Errorf("expect %d loads without cache, but got %d", 2, loads)
+	}
+
+	loads = 0
+	req = WithCache(req)
+	if r := WithCache(req); r != req {
+		t.Errorf("expect the same request, but got a new one")
+	}
+
+	for i := 0; i < 3; i++ {
+		if v := loadCache(req, key{}, load); v != 1 {
+			t.Errorf("expect cached value %d, but got %d", 1, v)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expect %d load with cache, but got %d", 1, loads)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ContentType returns a new matcher that checks whether the header
+// "Content-Type" matches one of the specified media type patterns.
+//
+// The media types are compared case-insensitively, and the pattern supports:
+//   - the exact media type, such as "application/json".
+//   - the type or subtype wildcard, such as "*/*" or "application/*".
+//   - the structured syntax suffix, such as "application/*+json",
+//     "*/*+json", or its shorthand "+json".
+//   - the parameter constraints, such as "text/plain; charset=utf-8",
+//     which must be present in the header with the case-insensitive
+//     value. The other parameters in the header are ignored.
+//
+// If patterns is empty, return (nil, nil) instead of an error.
+func ContentType(patterns ...string) (Matcher, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	cts := make([]contentTypePattern, len(patterns))
+	descs := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		ct, err := parseContentTypePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		cts[i] = ct
+		descs[i] = ct.String()
+	}
+
+	desc := argsdesc("ContentType", descs...)
+	canon := argsdesc("ContentType", sortedset(descs)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		ct := r.Header.Get("Content-Type")
+		if ct == "" {
+			return false
+		}
+
+		mtype, params, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return false
+		}
+
+		for _, _ct := range cts {
+			if _ct.Match(mtype, params) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+type contentTypePattern struct {
+	typ    string
+	sub    string
+	suffix string
+	params [][2]string
+}
+
+func (p contentTypePattern) String() string {
+	var b strings.Builder
+	b.WriteString(p.typ)
+	b.WriteByte('/')
+	b.WriteString(p.sub)
+	for _, param := range p.params {
+		b.WriteByte(';')
+		b.WriteString(param[0])
+		b.WriteByte('=')
+		b.WriteString(param[1])
+	}
+	return b.String()
+}
+
+func (p contentTypePattern) Match(mtype string, params map[string]string) bool {
+	typ, sub, _ := strings.Cut(mtype, "/")
+	if p.typ != "*" && p.typ != typ {
+		return false
+	}
+
+	switch {
+	case p.suffix != "":
+		if !strings.HasSuffix(sub, p.suffix) || len(sub) == len(p.suffix) {
+			return false
+		}
+
+	case p.sub != "*" && p.sub != sub:
+		return false
+	}
+
+	for _, param := range p.params {
+		if value, ok := params[param[0]]; !ok || !strings.EqualFold(value, param[1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseContentTypePattern(pattern string) (p contentTypePattern, err error) {
+	s := strings.TrimSpace(pattern)
+	if strings.HasPrefix(s, "+") {
+		s = "*/*" + s
+	}
+
+	mtype, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return p, fmt.Errorf("invalid content type pattern '%s': %w", pattern, err)
+	}
+
+	var ok bool
+	if p.typ, p.sub, ok = strings.Cut(mtype, "/"); !ok || p.typ == "" || p.sub == "" {
+		return p, fmt.Errorf("invalid content type pattern '%s'", pattern)
+	}
+
+	if p.typ == "*" && p.sub != "*" && !strings.HasPrefix(p.sub, "*+") {
+		return p, fmt.Errorf("invalid content type pattern '%s'", pattern)
+	}
+
+	if strings.HasPrefix(p.sub, "*+") {
+		p.suffix = p.sub[1:]
+	} else if strings.IndexByte(p.sub, '*') > -1 && p.sub != "*" {
+		return p, fmt.Errorf("invalid content type pattern '%s'", pattern)
+	}
+
+	if len(params) > 0 {
+		p.params = make([][2]string, 0, len(params))
+		for key, value := range params {
+			p.params = append(p.params, [2]string{key, value})
+		}
+		sort.Slice(p.params, func(i, j int) bool { return p.params[i][0] < p.params[j][0] })
+	}
+
+	return
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestContentType(t *testing.T) {
+	if m, err := ContentType(); err != nil {
+		t.Error(err)
+	} else if m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	for _, pattern := range []string{"application", "*/json", "application/js*"} {
+		if m, err := ContentType(pattern); err == nil {
+			t.Errorf("expect an error, but got a matcher '%s'", m.String())
+		}
+	}
+
+	req := &http.Request{Header: make(http.Header)}
+	req.Header.Set("Content-Type", "Application/Vnd.API+JSON; Charset=UTF-8")
+
+	for _, pattern := range []string{
+		"application/vnd.api+json",
+		"application/*",
+		"*/*",
+		"application/*+json",
+		"+json",
+		"application/*+json; charset=utf-8",
+	} {
+		if m, err := ContentType(pattern); err != nil {
+			t.Error(err)
+		} else if !m.Match(req) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		}
+	}
+
+	for _, pattern := range []string{
+		"application/json",
+		"text/*",
+		"+xml",
+		"application/*+json; charset=latin1",
+		"application/*+json; version=1",
+	} {
+		if m, err := ContentType(pattern); err != nil {
+			t.Error(err)
+		} else if m.Match(req) {
+			t.Errorf("unexpect match '%s', but got matched", m.String())
+		}
+	}
+
+	m, _ := ContentType("Text/Plain; Charset=utf-8", "+json")
+	if desc := m.String(); desc != "ContentType(`text/plain;charset=utf-8`,`*/*+json`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+}
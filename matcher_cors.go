@@ -0,0 +1,112 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"strings"
+)
+
+func isCORSPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// CORSPreflight returns a new matcher that checks whether the request
+// is a CORS preflight request, that's, the OPTIONS request with the headers
+// "Origin" and "Access-Control-Request-Method".
+func CORSPreflight() Matcher {
+	return New(PriorityHeader, "CORSPreflight()", isCORSPreflight)
+}
+
+// CORSPreflightMethods returns a new matcher that checks whether the request
+// is a CORS preflight request, and the requested method of the header
+// "Access-Control-Request-Method" is one of the allowed methods.
+//
+// If methods is empty, return nil instead of an error.
+func CORSPreflightMethods(methods ...string) Matcher {
+	if len(methods) == 0 {
+		return nil
+	}
+
+	_methods := make([]string, len(methods))
+	for i, method := range methods {
+		_methods[i] = strings.ToUpper(method)
+	}
+
+	desc := argsdesc("CORSPreflightMethods", _methods...)
+	canon := argsdesc("CORSPreflightMethods", sortedset(_methods)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		method := strings.TrimSpace(r.Header.Get("Access-Control-Request-Method"))
+		return isCORSPreflight(r) && contains(_methods, strings.ToUpper(method))
+	})
+}
+
+// CORSPreflightHeaders returns a new matcher that checks whether the request
+// is a CORS preflight request, and all the requested headers of the header
+// "Access-Control-Request-Headers" are allowed, which are compared
+// case-insensitively. "*" allows any header.
+//
+// The preflight request without the requested headers also matches.
+//
+// If headers is empty, return nil instead of an error.
+func CORSPreflightHeaders(headers ...string) Matcher {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	_headers := make([]string, len(headers))
+	for i, header := range headers {
+		_headers[i] = strings.ToLower(header)
+	}
+	anyHeader := contains(_headers, "*")
+
+	desc := argsdesc("CORSPreflightHeaders", _headers...)
+	canon := argsdesc("CORSPreflightHeaders", sortedset(_headers)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		if !isCORSPreflight(r) {
+			return false
+		} else if anyHeader {
+			return true
+		}
+
+		values := r.Header.Values("Access-Control-Request-Headers")
+		return !hasToken(values, func(header string) bool {
+			return !contains(_headers, strings.ToLower(header))
+		})
+	})
+}
+
+// CORSPreflightOrigins returns a new matcher that checks whether the request
+// is a CORS preflight request, and the header "Origin" matches one of the
+// origin patterns, which are the same as Origin.
+//
+// If origins is empty, return (nil, nil) instead of an error.
+func CORSPreflightOrigins(origins ...string) (Matcher, error) {
+	if len(origins) == 0 {
+		return nil, nil
+	}
+
+	ps, err := parseOriginPatterns(origins)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, canon := originPatternsDesc("CORSPreflightOrigins", ps)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		return isCORSPreflight(r) && matchOriginPatterns(ps, r.Header.Get("Origin"))
+	}), nil
+}
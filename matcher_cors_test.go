@@ -0,0 +1,108 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	newreq := func(method string, headers ...string) *http.Request {
+		req := &http.Request{Method: method, Header: make(http.Header)}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		return req
+	}
+
+	preflight := newreq(http.MethodOptions,
+		"Origin", "https://app.example.com",
+		"Access-Control-Request-Method", "PUT",
+		"Access-Control-Request-Headers", "content-type, X-Request-Id",
+	)
+
+	m := CORSPreflight()
+	if desc := m.String(); desc != "CORSPreflight()" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if !m.Match(preflight) {
+		t.Errorf("expect match the preflight request, but got not")
+	}
+
+	for _, req := range []*http.Request{
+		newreq(http.MethodOptions),
+		newreq(http.MethodOptions, "Origin", "https://app.example.com"),
+		newreq(http.MethodOptions, "Access-Control-Request-Method", "PUT"),
+		newreq(http.MethodPut, "Origin", "https://app.example.com", "Access-Control-Request-Method", "PUT"),
+	} {
+		if m.Match(req) {
+			t.Errorf("unexpect match the request %s with %v, but got matched", req.Method, req.Header)
+		}
+	}
+
+	if m := CORSPreflightMethods(); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+	if m := CORSPreflightMethods("get", "put"); !m.Match(preflight) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	} else if desc := m.String(); desc != "CORSPreflightMethods(`GET`,`PUT`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+	if m := CORSPreflightMethods("GET", "POST"); m.Match(preflight) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	if m := CORSPreflightHeaders(); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+	for _, m := range []Matcher{
+		CORSPreflightHeaders("Content-Type", "X-Request-ID"),
+		CORSPreflightHeaders("*"),
+	} {
+		if !m.Match(preflight) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		}
+	}
+	if m := CORSPreflightHeaders("Content-Type"); m.Match(preflight) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+	if m := CORSPreflightHeaders("Content-Type"); !m.Match(newreq(http.MethodOptions,
+		"Origin", "https://app.example.com", "Access-Control-Request-Method", "GET")) {
+		t.Errorf("expect match '%s' without the requested headers, but got not", m.String())
+	}
+
+	if m, err := CORSPreflightOrigins(); err != nil || m != nil {
+		t.Errorf("expect (nil, nil), but got (%v, %v)", m, err)
+	}
+	if _, err := CORSPreflightOrigins("https://app.example.com/path"); err == nil {
+		t.Errorf("expect an error for the invalid origin pattern, but got nil")
+	}
+
+	if m, err := CORSPreflightOrigins("https://*.example.com"); err != nil {
+		t.Error(err)
+	} else if !m.Match(preflight) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	} else if desc := m.String(); desc != "CORSPreflightOrigins(`https://*.example.com`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m, err := CORSPreflightOrigins("https://*.example.org"); err != nil {
+		t.Error(err)
+	} else if m.Match(preflight) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"strings"
+)
+
+// FetchSite returns a new matcher that checks whether the header
+// "Sec-Fetch-Site" is one of the specified values, that's,
+// "same-origin", "same-site", "cross-site" or "none".
+//
+// If sites is empty, return nil instead of an error.
+func FetchSite(sites ...string) Matcher {
+	return newFetchMetadataMatcher("FetchSite", "Sec-Fetch-Site", sites)
+}
+
+// FetchMode returns a new matcher that checks whether the header
+// "Sec-Fetch-Mode" is one of the specified values, such as "navigate",
+// "cors", "no-cors", "same-origin" or "websocket".
+//
+// If modes is empty, return nil instead of an error.
+func FetchMode(modes ...string) Matcher {
+	return newFetchMetadataMatcher("FetchMode", "Sec-Fetch-Mode", modes)
+}
+
+// FetchDest returns a new matcher that checks whether the header
+// "Sec-Fetch-Dest" is one of the specified values, such as "document",
+// "iframe", "image", "script", "style", "object", "embed" or "empty".
+//
+// If dests is empty, return nil instead of an error.
+func FetchDest(dests ...string) Matcher {
+	return newFetchMetadataMatcher("FetchDest", "Sec-Fetch-Dest", dests)
+}
+
+// FetchUser returns a new matcher that checks whether the request
+// is triggered by the user activation, that's, the header "Sec-Fetch-User"
+// is "?1".
+func FetchUser() Matcher {
+	return New(PriorityHeader, "FetchUser()", func(r *http.Request) bool {
+		return strings.TrimSpace(r.Header.Get("Sec-Fetch-User")) == "?1"
+	})
+}
+
+func newFetchMetadataMatcher(name, header string, values []string) Matcher {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_values := make([]string, len(values))
+	for i, value := range values {
+		_values[i] = strings.ToLower(value)
+	}
+
+	desc := argsdesc(name, _values...)
+	canon := argsdesc(name, sortedset(_values)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		value := strings.ToLower(strings.TrimSpace(r.Header.Get(header)))
+		return value != "" && contains(_values, value)
+	})
+}
+
+// FetchIsolationPolicy returns a new matcher implementing the Fetch Metadata
+// resource isolation policy, which matches the request if
+//
+//  1. the browser does not send the fetch metadata, that's, the header
+//     "Sec-Fetch-Site" is missing;
+//  2. or, the request is "same-origin", "same-site" or "none"
+//     (such as typing the url);
+//  3. or, the request is a GET navigation not to "object" or "embed";
+//  4. or, any of the exemptions matches, such as the public endpoints.
+//
+// So the unmatched requests should be rejected to protect against
+// the CSRF and XS-leaks. It can be composed with And and Or.
+func FetchIsolationPolicy(exemptions ...Matcher) Matcher {
+	Sort(exemptions)
+	descs := make([]string, len(exemptions))
+	canons := make([]string, len(exemptions))
+	for i, m := range exemptions {
+		descs[i], canons[i] = m.String(), Canonical(m)
+	}
+
+	desc := "FetchIsolationPolicy(" + strings.Join(descs, orsep) + ")"
+	canon := "FetchIsolationPolicy(" + strings.Join(sortedset(canons), orsep) + ")"
+
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		switch strings.ToLower(strings.TrimSpace(r.Header.Get("Sec-Fetch-Site"))) {
+		case "", "same-origin", "same-site", "none":
+			return true
+		}
+
+		if r.Method == http.MethodGet && strings.EqualFold(r.Header.Get("Sec-Fetch-Mode"), "navigate") {
+			switch strings.ToLower(r.Header.Get("Sec-Fetch-Dest")) {
+			case "object", "embed":
+			default:
+				return true
+			}
+		}
+
+		for _, m := range exemptions {
+			if m.Match(r) {
+				return true
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func newFetchRequest(method, path string, headers ...string) *http.Request {
+	req := &http.Request{Method: method, URL: &url.URL{Path: path}, Header: make(http.Header)}
+	for i := 0; i < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	return req
+}
+
+func TestFetchMetadata(t *testing.T) {
+	if m := FetchSite(); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := newFetchRequest(http.MethodGet, "/",
+		"Sec-Fetch-Site", "cross-site",
+		"Sec-Fetch-Mode", "navigate",
+		"Sec-Fetch-Dest", "document",
+		"Sec-Fetch-User", "?1",
+	)
+
+	for _, m := range []Matcher{
+		FetchSite("Cross-Site"),
+		FetchMode("navigate", "cors"),
+		FetchDest("document"),
+		FetchUser(),
+	} {
+		if !m.Match(req) {
+			t.Errorf("expect match '%s', but got not", m.String())
+		}
+	}
+
+	for _, m := range []Matcher{
+		FetchSite("same-origin"),
+		FetchMode("cors"),
+		FetchDest("image", "script"),
+	} {
+		if m.Match(req) {
+			t.Errorf("unexpect match '%s', but got matched", m.String())
+		}
+	}
+
+	if FetchUser().Match(newFetchRequest(http.MethodGet, "/")) {
+		t.Errorf("unexpect match FetchUser without the header, but got matched")
+	}
+
+	if desc := FetchSite("same-site", "same-origin").String(); desc != "FetchSite(`same-site`,`same-origin`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+}
+
+func TestFetchIsolationPolicy(t *testing.T) {
+	policy := FetchIsolationPolicy()
+	if desc := policy.String(); desc != "FetchIsolationPolicy()" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	for _, req := range []*http.Request{
+		newFetchRequest(http.MethodPost, "/api"),
+		newFetchRequest(http.MethodPost, "/api", "Sec-Fetch-Site", "same-origin", "Sec-Fetch-Mode", "cors"),
+		newFetchRequest(http.MethodPost, "/api", "Sec-Fetch-Site", "same-site", "Sec-Fetch-Mode", "cors"),
+		newFetchRequest(http.MethodGet, "/", "Sec-Fetch-Site", "none", "Sec-Fetch-Mode", "navigate"),
+		newFetchRequest(http.MethodGet, "/", "Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "navigate", "Sec-Fetch-Dest", "document"),
+	} {
+		if !policy.Match(req) {
+			t.Errorf("expect match the request %s %s with %v, but got not", req.Method, req.URL.Path, req.Header)
+		}
+	}
+
+	for _, req := range []*http.Request{
+		newFetchRequest(http.MethodPost, "/api", "Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "cors"),
+		newFetchRequest(http.MethodPost, "/", "Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "navigate"),
+		newFetchRequest(http.MethodGet, "/", "Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "navigate", "Sec-Fetch-Dest", "embed"),
+		newFetchRequest(http.MethodGet, "/image", "Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "no-cors", "Sec-Fetch-Dest", "image"),
+	} {
+		if policy.Match(req) {
+			t.Errorf("unexpect match the request %s %s with %v, but got matched", req.Method, req.URL.Path, req.Header)
+		}
+	}
+
+	policy = FetchIsolationPolicy(PathPrefix("/public"), Path("/webhook"))
+	if desc := policy.String(); desc != "FetchIsolationPolicy(Path(`/webhook`) || PathPrefix(`/public`))" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	req := newFetchRequest(http.MethodGet, "/public/logo.png", "Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "no-cors")
+	if !policy.Match(req) {
+		t.Errorf("expect match the exempted request, but got not")
+	}
+
+	if m := And(Method(http.MethodPost), policy); m.Match(newFetchRequest(http.MethodPost, "/api",
+		"Sec-Fetch-Site", "cross-site", "Sec-Fetch-Mode", "cors")) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileCheckInterval is the minimum interval to check whether the local file,
+// such as the htpasswd file, has changed and should be reloaded.
+var FileCheckInterval = time.Second
+
+// reloadFile is a local file which is parsed into a value,
+// and reloaded when its modification time or size has changed.
+type reloadFile[T any] struct {
+	path  string
+	parse func([]byte) (T, error)
+
+	lock    sync.Mutex
+	value   T
+	modtime time.Time
+	size    int64
+	checked time.Time
+}
+
+// newReloadFile loads and parses the file, and returns it.
+func newReloadFile[T any](path string, parse func([]byte) (T, error)) (*reloadFile[T], error) {
+	f := &reloadFile[T]{path: path, parse: parse}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *reloadFile[T]) load() error {
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	value, err := f.parse(data)
+	if err != nil {
+		return err
+	}
+
+	f.value, f.modtime, f.size = value, fi.ModTime(), fi.Size()
+	return nil
+}
+
+// Get returns the parsed value of the file, which reloads the file
+// if it has changed since the last check. If failing to reload it,
+// log the error and return the last value.
+func (f *reloadFile[T]) Get() T {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if now := time.Now(); now.Sub(f.checked) >= FileCheckInterval {
+		f.checked = now
+		if fi, err := os.Stat(f.path); err != nil {
+			slog.Error("fail to stat the file", "file", f.path, "err", err)
+		} else if !fi.ModTime().Equal(f.modtime) || fi.Size() != f.size {
+			if err := f.load(); err != nil {
+				slog.Error("fail to reload the file", "file", f.path, "err", err)
+			}
+		}
+	}
+
+	return f.value
+}
@@ -0,0 +1,352 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+type graphqlCacheKey struct{}
+
+// graphqlOperation is the operation selected from a GraphQL request.
+type graphqlOperation struct {
+	Type string // "query", "mutation", "subscription" or "" if unknown.
+	Name string
+	Hash string // The sha256 hash of the persisted query.
+}
+
+type graphqlRequest struct {
+	Query         string
+	OperationName string
+	Extensions    json.RawMessage
+}
+
+// getGraphQLOperations extracts the GraphQL operations from the request,
+// which are cached if the request is returned by WithCache.
+//
+// It supports the GET request with the query arguments "query",
+// "operationName" and "extensions", the POST request with the json body
+// of a single request or a batch of requests, and the POST request
+// with the content type "application/graphql".
+func getGraphQLOperations(r *http.Request) []graphqlOperation {
+	return loadCache(r, graphqlCacheKey{}, func() []graphqlOperation {
+		switch r.Method {
+		case http.MethodGet:
+			query, ok := getQuery(r)
+			if !ok {
+				return nil
+			}
+
+			req := graphqlRequest{
+				Query:         query.Get("query"),
+				OperationName: query.Get("operationName"),
+				Extensions:    json.RawMessage(query.Get("extensions")),
+			}
+			return appendGraphQLOperation(nil, req)
+
+		case http.MethodPost:
+			mtype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			switch mtype {
+			case "application/graphql":
+				data, err := getBody(r)
+				if err != nil {
+					return nil
+				}
+
+				var req graphqlRequest
+				req.Query = string(data)
+				if query, ok := getQuery(r); ok {
+					req.OperationName = query.Get("operationName")
+				}
+				return appendGraphQLOperation(nil, req)
+
+			case "application/json":
+				data, err := getBody(r)
+				if err != nil {
+					return nil
+				}
+
+				data = bytes.TrimSpace(data)
+				if len(data) > 0 && data[0] == '[' {
+					var reqs []json.RawMessage
+					if json.Unmarshal(data, &reqs) != nil {
+						return nil
+					}
+
+					var ops []graphqlOperation
+					for _, data := range reqs {
+						ops = appendGraphQLJSONOperation(ops, data)
+					}
+					return ops
+				}
+
+				return appendGraphQLJSONOperation(nil, data)
+			}
+		}
+
+		return nil
+	})
+}
+
+// appendGraphQLJSONOperation decodes the GraphQL request from the json object
+// and appends its operation.
+//
+// Since encoding/json matches the field names case-insensitively, but the
+// GraphQL servers in other languages do not, only the exact keys are read.
+// If the object has the case variants of a key, such as "QUERY", the request
+// is ambiguous and its operation is unknown, which matches no type and name.
+func appendGraphQLJSONOperation(ops []graphqlOperation, data []byte) []graphqlOperation {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return ops
+	}
+
+	for key := range fields {
+		switch strings.ToLower(key) {
+		case "query", "operationname", "extensions":
+			if key != "query" && key != "operationName" && key != "extensions" {
+				return append(ops, graphqlOperation{})
+			}
+		}
+	}
+
+	var req graphqlRequest
+	_ = json.Unmarshal(fields["query"], &req.Query)
+	_ = json.Unmarshal(fields["operationName"], &req.OperationName)
+	req.Extensions = fields["extensions"]
+	return appendGraphQLOperation(ops, req)
+}
+
+func appendGraphQLOperation(ops []graphqlOperation, req graphqlRequest) []graphqlOperation {
+	var op graphqlOperation
+	if len(req.Extensions) > 0 {
+		var ext struct {
+			PersistedQuery struct {
+				Sha256Hash string `json:"sha256Hash"`
+			} `json:"persistedQuery"`
+		}
+		if json.Unmarshal(req.Extensions, &ext) == nil {
+			op.Hash = ext.PersistedQuery.Sha256Hash
+		}
+	}
+
+	if req.Query == "" && op.Hash == "" {
+		return ops
+	}
+
+	op.Name = req.OperationName
+	if req.Query != "" {
+		op.Type, op.Name = selectGraphQLOperation(parseGraphQLDocument(req.Query), req.OperationName)
+	}
+	return append(ops, op)
+}
+
+// selectGraphQLOperation selects the operation by the name from the operations
+// defined in the document. If name is empty, the document must have only one
+// operation. If failing, return the empty type.
+func selectGraphQLOperation(ops [][2]string, name string) (_type, _name string) {
+	if name == "" {
+		if len(ops) == 1 {
+			return ops[0][0], ops[0][1]
+		}
+		return "", ""
+	}
+
+	for _, op := range ops {
+		if op[1] == name {
+			return op[0], name
+		}
+	}
+	return "", name
+}
+
+// parseGraphQLDocument parses the type and name of the operations defined
+// in the GraphQL document, which only scans the headers of the top-level
+// definitions and skips the selection sets, the comments and the strings.
+func parseGraphQLDocument(doc string) (ops [][2]string) {
+	var depth int
+	var inDefinition bool // Whether in the header of a top-level definition.
+	var expectName bool   // Whether the next name is the operation name.
+	for i, _len := 0, len(doc); i < _len; {
+		switch c := doc[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+
+		case c == '#':
+			for i < _len && doc[i] != '\n' && doc[i] != '\r' {
+				i++
+			}
+
+		case c == '"':
+			if i = skipGraphQLString(doc, i); i < 0 {
+				return
+			}
+
+		case isGraphQLNameStart(c):
+			start := i
+			for i < _len && isGraphQLNameChar(doc[i]) {
+				i++
+			}
+
+			switch name := doc[start:i]; {
+			case depth > 0:
+			case expectName:
+				ops[len(ops)-1][1] = name
+				expectName = false
+
+			case inDefinition:
+			case name == "query" || name == "mutation" || name == "subscription":
+				ops = append(ops, [2]string{name, ""})
+				inDefinition, expectName = true, true
+
+			default: // Such as the fragment definition.
+				inDefinition = true
+			}
+
+		case c == '{' || c == '(' || c == '[':
+			if depth == 0 && c == '{' && !inDefinition {
+				ops = append(ops, [2]string{"query", ""}) // The query shorthand.
+				inDefinition = true
+			}
+
+			expectName = false
+			depth++
+			i++
+
+		case c == '}' || c == ')' || c == ']':
+			if depth > 0 {
+				if depth--; depth == 0 && c == '}' {
+					inDefinition = false
+				}
+			}
+			i++
+
+		default:
+			if depth == 0 {
+				expectName = false
+			}
+			i++
+		}
+	}
+	return
+}
+
+// skipGraphQLString skips the string or block string starting from doc[i],
+// and returns the index after it. If the string is not terminated, return -1.
+func skipGraphQLString(doc string, i int) int {
+	if strings.HasPrefix(doc[i:], `"""`) {
+		for i += 3; i < len(doc); i++ {
+			switch {
+			case strings.HasPrefix(doc[i:], `\"""`):
+				i += 3
+			case strings.HasPrefix(doc[i:], `"""`):
+				return i + 3
+			}
+		}
+		return -1
+	}
+
+	for i++; i < len(doc); i++ {
+		switch doc[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		case '\n', '\r':
+			return -1
+		}
+	}
+	return -1
+}
+
+func isGraphQLNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isGraphQLNameChar(c byte) bool {
+	return isGraphQLNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// GraphQLOperationType returns a new matcher that checks whether the GraphQL
+// request has an operation of one of the specified types, that's,
+// "query", "mutation" or "subscription".
+//
+// For the batched requests, it matches if any of the operations matches,
+// so that the requests containing a mutation can be gated separately.
+//
+// The type of the automatic persisted query only carrying the hash
+// without the query document is unknown, so it never matches any type,
+// including "mutation". So it is safer to allow the expected types
+// than to deny "mutation".
+//
+// If types is empty, return nil instead of an error.
+func GraphQLOperationType(types ...string) Matcher {
+	if len(types) == 0 {
+		return nil
+	}
+
+	_types := make([]string, len(types))
+	for i, _type := range types {
+		_types[i] = strings.ToLower(_type)
+	}
+
+	return newGraphQLMatcher("GraphQLOperationType", _types, func(op graphqlOperation) bool {
+		return contains(_types, op.Type)
+	})
+}
+
+// GraphQLOperationName returns a new matcher that checks whether the GraphQL
+// request has an operation with one of the specified names.
+//
+// For the batched requests, it matches if any of the operations matches.
+//
+// If names is empty, return nil instead of an error.
+func GraphQLOperationName(names ...string) Matcher {
+	if len(names) == 0 {
+		return nil
+	}
+
+	return newGraphQLMatcher("GraphQLOperationName", names, func(op graphqlOperation) bool {
+		return contains(names, op.Name)
+	})
+}
+
+// GraphQLPersistedQuery returns a new matcher that checks whether the GraphQL
+// request has a persisted query with one of the specified sha256 hashes,
+// which is carried by "extensions.persistedQuery.sha256Hash".
+//
+// If hashes is empty, it matches any persisted query.
+func GraphQLPersistedQuery(hashes ...string) Matcher {
+	return newGraphQLMatcher("GraphQLPersistedQuery", hashes, func(op graphqlOperation) bool {
+		return op.Hash != "" && (len(hashes) == 0 || contains(hashes, op.Hash))
+	})
+}
+
+func newGraphQLMatcher(name string, args []string, match func(graphqlOperation) bool) Matcher {
+	desc := argsdesc(name, args...)
+	canon := argsdesc(name, sortedset(args)...)
+	return newc(PriorityBody, desc, canon, func(r *http.Request) bool {
+		for _, op := range getGraphQLOperations(r) {
+			if match(op) {
+				return true
+			}
+		}
+		return false
+	})
+}
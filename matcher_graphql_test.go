@@ -0,0 +1,114 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGraphQLDocument(t *testing.T) {
+	doc := `
+	# query Comment { a }
+	query GetUser($id: ID! = "x}") @cached { user(id: $id) { ...F name(arg: "mutation X {") } }
+	fragment F on User { id }
+	mutation
+	  UpdateUser($input: [In!]! = [{a: 1}]) { update(input: $input) { id desc(s: """a \""" }""") } }
+	subscription { onEvent { id } }
+	{ viewer { id } }
+	`
+
+	expect := [][2]string{
+		{"query", "GetUser"},
+		{"mutation", "UpdateUser"},
+		{"subscription", ""},
+		{"query", ""},
+	}
+	if ops := parseGraphQLDocument(doc); !reflect.DeepEqual(expect, ops) {
+		t.Errorf("expect %v, but got %v", expect, ops)
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	newpost := func(body string) *http.Request {
+		req := &http.Request{
+			Method: http.MethodPost,
+			URL:    &url.URL{Path: "/graphql"},
+			Header: make(http.Header),
+			Body:   io.NopCloser(strings.NewReader(body)),
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return WithCache(req)
+	}
+
+	req := newpost(`{"query":"query A { a } mutation B { b }","operationName":"B"}`)
+	if m := GraphQLOperationType("mutation"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := GraphQLOperationName("B"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := GraphQLOperationType("query"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req = newpost(`[{"query":"{ a }"},{"query":"mutation { b }"}]`)
+	if m := GraphQLOperationType("Mutation"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	// The case variants of the keys are ambiguous, since encoding/json
+	// matches them case-insensitively, but the other servers may not.
+	for _, body := range []string{
+		`{"query":"mutation { deleteAll }","QUERY":"query { me }"}`,
+		`[{"query":"mutation { deleteAll }","Query":"query { me }"}]`,
+	} {
+		req = newpost(body)
+		if m := GraphQLOperationType("query"); m.Match(req) {
+			t.Errorf("unexpect match '%s' for %s, but got matched", m.String(), body)
+		}
+		if m := GraphQLOperationType("mutation"); m.Match(req) {
+			t.Errorf("unexpect match '%s' for %s, but got matched", m.String(), body)
+		}
+	}
+
+	req = newpost(`{"operationName":"Q","extensions":{"persistedQuery":{"version":1,"sha256Hash":"abc"}}}`)
+	if m := GraphQLPersistedQuery(); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := GraphQLPersistedQuery("abc"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := GraphQLOperationName("Q"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	req = &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Path: "/graphql", RawQuery: url.Values{"query": []string{"query Q { a }"}}.Encode()},
+	}
+	if m := GraphQLOperationType("query"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	} else if desc := m.String(); desc != "GraphQLOperationType(`query`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+	if m := GraphQLOperationType("mutation"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	grpcNone = iota
+	grpcNative
+	grpcWeb
+	grpcGateway
+)
+
+// GRPC returns a new matcher that checks whether the request is a gRPC
+// request, which is a POST request over HTTP/2 with the content type
+// "application/grpc" or "application/grpc+CODEC", or a gRPC-Web request
+// over any http version with the content type "application/grpc-web",
+// "application/grpc-web-text" and their "+CODEC" variants.
+//
+// The path must be in the form of "/package.Service/Method".
+func GRPC() Matcher {
+	return New(PriorityGRPC, "GRPC()", func(r *http.Request) bool {
+		switch getGRPCKind(r) {
+		case grpcNative, grpcWeb:
+			_, _, ok := splitGRPCPath(GetPath(r))
+			return ok
+		default:
+			return false
+		}
+	})
+}
+
+// GRPCService returns a new matcher that checks whether the request
+// calls a method of one of the specified gRPC services, such as "pkg.Svc".
+//
+// Besides the gRPC and gRPC-Web requests, it also matches the gRPC-Gateway
+// style requests, which are the POST requests with the content type
+// "application/json" and the path "/package.Service/Method".
+//
+// If services is empty, return nil instead of an error.
+func GRPCService(services ...string) Matcher {
+	if len(services) == 0 {
+		return nil
+	}
+
+	var maxlen int
+	_services := make(exactFullMatches, len(services))
+	for i, service := range services {
+		_services[i] = strings.Trim(service, "/")
+		if _len := len(_services[i]) + 1; _len > maxlen {
+			maxlen = _len
+		}
+	}
+
+	desc := argsdesc("GRPCService", _services...)
+	canon := argsdesc("GRPCService", sortedset(_services)...)
+	return newc(PriorityPathPrefix*maxlen+PriorityGRPC, desc, canon, func(r *http.Request) bool {
+		if getGRPCKind(r) == grpcNone {
+			return false
+		}
+
+		service, _, ok := splitGRPCPath(GetPath(r))
+		return ok && _services.Match(service)
+	})
+}
+
+// GRPCMethod returns a new matcher that checks whether the request
+// calls one of the specified gRPC methods, such as "pkg.Svc/Method".
+//
+// Like GRPCService, it also matches the gRPC-Gateway style requests.
+//
+// If methods is empty, return nil instead of an error.
+func GRPCMethod(methods ...string) Matcher {
+	if len(methods) == 0 {
+		return nil
+	}
+
+	var maxlen int
+	paths := make(exactFullMatches, len(methods))
+	for i, method := range methods {
+		paths[i] = "/" + strings.Trim(method, "/")
+		if _len := len(paths[i]); _len > maxlen {
+			maxlen = _len
+		}
+	}
+
+	descs := make([]string, len(paths))
+	for i, path := range paths {
+		descs[i] = path[1:]
+	}
+
+	desc := argsdesc("GRPCMethod", descs...)
+	canon := argsdesc("GRPCMethod", sortedset(descs)...)
+	return newc(PriorityPath*maxlen+PriorityGRPC, desc, canon, func(r *http.Request) bool {
+		if getGRPCKind(r) == grpcNone {
+			return false
+		}
+
+		path := GetPath(r)
+		_, _, ok := splitGRPCPath(path)
+		return ok && paths.Match(path)
+	})
+}
+
+func getGRPCKind(r *http.Request) int {
+	if r.Method != http.MethodPost {
+		return grpcNone
+	}
+
+	ct := strings.ToLower(getContentType(r))
+	switch {
+	case ct == "application/grpc-web" || strings.HasPrefix(ct, "application/grpc-web+"),
+		ct == "application/grpc-web-text" || strings.HasPrefix(ct, "application/grpc-web-text+"):
+		return grpcWeb
+
+	case ct == "application/grpc" || strings.HasPrefix(ct, "application/grpc+"):
+		if r.ProtoMajor < 2 {
+			return grpcNone
+		}
+		return grpcNative
+
+	case ct == "application/json":
+		return grpcGateway
+
+	default:
+		return grpcNone
+	}
+}
+
+// splitGRPCPath splits the path in the form of "/package.Service/Method".
+func splitGRPCPath(path string) (service, method string, ok bool) {
+	if len(path) < 4 || path[0] != '/' {
+		return
+	}
+
+	service, method, ok = strings.Cut(path[1:], "/")
+	ok = ok && service != "" && method != "" && strings.IndexByte(method, '/') < 0
+	return
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestGRPC(t *testing.T) {
+	req := &http.Request{
+		Method:     http.MethodPost,
+		ProtoMajor: 2,
+		URL:        &url.URL{Path: "/pkg.Svc/Method"},
+		Header:     http.Header{"Content-Type": []string{"application/grpc+proto"}},
+	}
+
+	if m := GRPC(); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := GRPCService("pkg.Svc", "pkg.Other"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := GRPCMethod("pkg.Svc/Method"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	} else if desc := m.String(); desc != "GRPCMethod(`pkg.Svc/Method`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m := GRPCMethod("pkg.Svc/Other"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.ProtoMajor = 1
+	if m := GRPC(); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.Header.Set("Content-Type", "application/grpc-web-text")
+	if m := GRPC(); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if m := GRPC(); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+	if m := GRPCService("pkg.Svc"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	req.URL.Path = "/pkg.Svc/Method/Other"
+	if m := GRPCService("pkg.Svc"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	if p1, p2 := GRPCService("pkg.Svc").Priority(), PathPrefix("/pkg.Svc").Priority(); p1 <= p2 {
+		t.Errorf("expect priority %d is greater than %d", p1, p2)
+	}
+}
@@ -48,6 +48,7 @@ func Host(hosts ...string) Matcher {
 	}
 
 	var maxlen int
+	_hosts := make([]string, len(hosts))
 	matches := make([]func(string) bool, len(hosts))
 	for i, host := range hosts {
 		host = strings.ToLower(host)
@@ -55,10 +56,12 @@ func Host(hosts ...string) Matcher {
 		if _len := len(host); _len > maxlen {
 			maxlen = _len
 		}
+		_hosts[i] = host
 	}
 
-	desc := fmt.Sprintf("Host(`%s`)", strings.Join(hosts, "`,`"))
-	return New(PriorityHost*maxlen, desc, func(r *http.Request) bool {
+	desc := argsdesc("Host", _hosts...)
+	canon := argsdesc("Host", sortedset(_hosts)...)
+	return newc(PriorityHost*maxlen, desc, canon, func(r *http.Request) bool {
 		host := GetHost(r)
 		for _, match := range matches {
 			if match(host) {
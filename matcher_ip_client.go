@@ -15,10 +15,8 @@
 package matcher
 
 import (
-	"fmt"
 	"net/http"
 	"net/netip"
-	"strings"
 )
 
 // GetClientIP is used to customize the client ip.
@@ -40,8 +38,9 @@ func ClientIP(ips ...string) (Matcher, error) {
 		return nil, err
 	}
 
-	desc := fmt.Sprintf("ClientIp(`%s`)", strings.Join(ips, "`,`"))
-	return New(PriorityClientIP, desc, func(r *http.Request) bool {
+	desc := argsdesc("ClientIp", ips...)
+	canon := argsdesc("ClientIp", sortedset(checker.Strings())...)
+	return newc(PriorityClientIP, desc, canon, func(r *http.Request) bool {
 		return checker.ContainsAddr(GetClientIP(r))
 	}), nil
 }
@@ -15,12 +15,10 @@
 package matcher
 
 import (
-	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/netip"
-	"strings"
 )
 
 // GetServerIP is used to customize the server ip.
@@ -52,8 +50,9 @@ func ServerIP(ips ...string) (Matcher, error) {
 		return nil, err
 	}
 
-	desc := fmt.Sprintf("ServerIp(`%s`)", strings.Join(ips, "`,`"))
-	return New(PriorityServerIP, desc, func(r *http.Request) bool {
+	desc := argsdesc("ServerIp", ips...)
+	canon := argsdesc("ServerIp", sortedset(checker.Strings())...)
+	return newc(PriorityServerIP, desc, canon, func(r *http.Request) bool {
 		return checker.ContainsAddr(GetServerIP(r))
 	}), nil
 }
@@ -0,0 +1,76 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import "net/http"
+
+type cookieCacheKey struct{}
+
+// getCookies returns the cookies of the request, which are parsed only once
+// if the request is returned by WithCache. The values of the cookies
+// with the same name are collected in order, and the quoted values
+// are unquoted.
+func getCookies(r *http.Request) map[string][]string {
+	return loadCache(r, cookieCacheKey{}, func() map[string][]string {
+		cookies := r.Cookies()
+		values := make(map[string][]string, len(cookies))
+		for _, c := range cookies {
+			values[c.Name] = append(values[c.Name], unquote(c.Value))
+		}
+		return values
+	})
+}
+
+// Cookie returns a new matcher that checks whether the cookies
+// has the specified name-value argument.
+//
+// Both name and value are the exact match. If there are the duplicated
+// cookies with the same name, any of them matches.
+// If value is empty, it matches all the requests that has the cookie name
+// and ignores the cookie value.
+//
+// If name is empty, return nil instead of an error.
+func Cookie(name, value string) Matcher {
+	if name == "" {
+		return nil
+	}
+
+	desc := kvdesc("Cookie", name, value)
+	return New(PriorityCookie, desc, func(r *http.Request) bool {
+		values, ok := getCookies(r)[name]
+		if !ok || (value != "" && !contains(values, value)) {
+			return false
+		}
+		return true
+	})
+}
+
+// CookieValue returns a new matcher that checks whether the values
+// of the cookie name match the value matcher by the mode.
+//
+// The name is the exact match. If the request has no the cookie,
+// it does not match.
+//
+// If name is empty or vm is nil, return nil instead of an error.
+func CookieValue(name string, mode ValuesMode, vm ValueMatcher) Matcher {
+	if name == "" || vm == nil {
+		return nil
+	}
+
+	desc := vmdesc("Cookie", name, mode, vm)
+	return New(PriorityCookie, desc, func(r *http.Request) bool {
+		return mode.Match(getCookies(r)[name], vm)
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCookie(t *testing.T) {
+	if m := Cookie("", ""); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := &http.Request{Header: make(http.Header, 1)}
+	req.Header.Set("Cookie", `session=abc; theme="dark"; session=def`)
+	req = WithCache(req)
+
+	if m := Cookie("session", ""); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	} else if desc := m.String(); desc != "Cookie(`session`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m := Cookie("session", "def"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := Cookie("theme", "dark"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := Cookie("lang", ""); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	if m := CookieValue("session", AllValues, Glob("???")); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := CookieValue("session", AllValues, Equal("abc")); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
@@ -15,6 +15,7 @@
 package matcher
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 )
@@ -73,7 +74,8 @@ func Headerm(headerm map[string]string) Matcher {
 	}
 
 	desc := kvsdesc("Header", headers)
-	return New(PriorityHeader*len(headers), desc, func(r *http.Request) bool {
+	conjs := kvsconjs("Header", headers)
+	return newcs(PriorityHeader*len(headers), desc, conjs, func(r *http.Request) bool {
 		for key, value := range headers {
 			switch {
 			case value == "":
@@ -98,6 +100,169 @@ func Headerm(headerm map[string]string) Matcher {
 	})
 }
 
+// Headerms returns a new matcher that checks whether the headers
+// has all the specified keys, and the value of each key is any of
+// the specified values.
+//
+// The keys are the case-insensitive match, but, the values are the exact match.
+// If values is empty, it matches all the requests that has the header key
+// and ignores the header value.
+//
+// If headerms is empty, return nil instead of an error.
+func Headerms(headerms map[string][]string) Matcher {
+	if len(headerms) == 0 {
+		return nil
+	}
+
+	headers := make(map[string][]string, len(headerms))
+	for key, values := range headerms {
+		key = http.CanonicalHeaderKey(key)
+		headers[key] = append(headers[key], values...)
+	}
+
+	kvs := newkvsm(headers)
+	desc := kvsmdesc("Header", kvs)
+	conjs := kvsmconjs("Header", kvs)
+	return newcs(PriorityHeader*len(kvs), desc, conjs, func(r *http.Request) bool {
+		for _, kv := range kvs {
+			switch {
+			case len(kv.values) == 0:
+				if _, ok := r.Header[kv.key]; !ok {
+					return false
+				}
+
+			case kv.key == "Content-Type":
+				if !kv.values.Match(getContentType(r)) {
+					return false
+				}
+
+			default:
+				if !kv.MatchAny(r.Header[kv.key]) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// HeaderValue returns a new matcher that checks whether the values
+// of the header key match the value matcher by the mode.
+//
+// The key is the case-insensitive match. If the request has no the header,
+// it does not match. Like Header, the value of "Content-Type" is the media
+// type without the parameters.
+//
+// If key is empty or vm is nil, return nil instead of an error.
+func HeaderValue(key string, mode ValuesMode, vm ValueMatcher) Matcher {
+	if key == "" || vm == nil {
+		return nil
+	}
+
+	key = http.CanonicalHeaderKey(key)
+	desc := vmdesc("Header", key, mode, vm)
+	if key == "Content-Type" {
+		return New(PriorityHeader, desc, func(r *http.Request) bool {
+			_, ok := r.Header[key]
+			return ok && mode.Match([]string{getContentType(r)}, vm)
+		})
+	}
+
+	return New(PriorityHeader, desc, func(r *http.Request) bool {
+		return mode.Match(r.Header[key], vm)
+	})
+}
+
+// HeaderAbsent returns a new matcher that checks whether the request
+// does not have the header key, which is the case-insensitive match.
+//
+// If key is empty, return nil instead of an error.
+func HeaderAbsent(key string) Matcher {
+	if key == "" {
+		return nil
+	}
+
+	key = http.CanonicalHeaderKey(key)
+	desc := fmt.Sprintf("!Header(`%s`)", key)
+	return New(PriorityHeader, desc, func(r *http.Request) bool {
+		_, ok := r.Header[key]
+		return !ok
+	})
+}
+
+// HeaderToken returns a new matcher that checks whether the header key,
+// which is a comma-separated list such as "Accept-Encoding"
+// or "Cache-Control", contains one of the specified tokens.
+//
+// The list is split by the list syntax of RFC 9110, which respects
+// the quoted strings, and the whitespaces around each element are trimmed.
+// The token name is the case-insensitive match. If the specified token
+// has no value or parameters, such as "br" or "max-age", the value
+// and parameters of the element are ignored. Or, such as "max-age=60"
+// or "gzip;q=0", the element must also have the same value and all
+// the specified parameters with the same values.
+//
+// If key is empty or tokens is empty, return nil instead of an error.
+func HeaderToken(key string, tokens ...string) Matcher {
+	if key == "" || len(tokens) == 0 {
+		return nil
+	}
+
+	type token struct {
+		name   string
+		value  string
+		valued bool
+		params [][2]string
+	}
+
+	_tokens := make([]token, len(tokens))
+	for i, t := range tokens {
+		name, params := splitParams(t)
+		name, value, valued := strings.Cut(name, "=")
+		_tokens[i] = token{
+			name:   strings.ToLower(strings.TrimSpace(name)),
+			value:  unquote(strings.TrimSpace(value)),
+			valued: valued,
+			params: params,
+		}
+	}
+
+	key = http.CanonicalHeaderKey(key)
+	desc := argsdesc("HeaderToken", append([]string{key}, tokens...)...)
+	canon := argsdesc("HeaderToken", append([]string{key}, sortedset(tokens)...)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		return hasToken(r.Header[key], func(element string) bool {
+			name, params := splitParams(element)
+			name, value, _ := strings.Cut(name, "=")
+			name = strings.TrimSpace(name)
+			value = unquote(strings.TrimSpace(value))
+			for _, t := range _tokens {
+				if strings.EqualFold(name, t.name) && (!t.valued || value == t.value) &&
+					containsParams(params, t.params) {
+					return true
+				}
+			}
+			return false
+		})
+	})
+}
+
+func containsParams(params, subparams [][2]string) bool {
+	for _, sub := range subparams {
+		var found bool
+		for _, param := range params {
+			if param[0] == sub[0] {
+				found = param[1] == sub[1]
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func getContentType(r *http.Request) (ct string) {
 	ct = r.Header.Get("Content-Type")
 	if index := strings.IndexByte(ct, ';'); index > -1 {
@@ -66,3 +66,135 @@ func TestHeaderm(t *testing.T) {
 		t.Errorf("unexpect match '%v', but got matched", req.Header)
 	}
 }
+
+func TestHeaderValue(t *testing.T) {
+	if m := HeaderValue("", AnyValue, Equal("v")); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := &http.Request{Header: make(http.Header, 2)}
+	req.Header.Set("User-Agent", "curl/8.4.0")
+	req.Header.Add("X-Tag", "prod-a")
+	req.Header.Add("X-Tag", "stage-b")
+
+	re, err := Regexp(`^curl/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := HeaderValue("user-agent", AnyValue, re)
+	if desc := m.String(); desc != "Header(`User-Agent`,~`^curl/`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+	if !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+
+	if m := HeaderValue("X-Tag", AnyValue, Prefix("stage-")); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+
+	if m := HeaderValue("X-Tag", AllValues, Prefix("stage-")); m.Match(req) {
+		t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+	}
+
+	if m := HeaderValue("X-Tag", AllValues, Glob("*-?")); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	} else if desc := m.String(); desc != "Header(`X-Tag`,all g`*-?`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m := HeaderValue("X-Missing", AllValues, Contains("")); m.Match(req) {
+		t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if m := HeaderValue("Content-Type", AnyValue, Equal("application/json")); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+}
+
+func TestHeaderAbsent(t *testing.T) {
+	req := &http.Request{Header: make(http.Header, 1)}
+	req.Header.Set("k1", "v1")
+
+	if m := HeaderAbsent("K1"); m.Match(req) {
+		t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+	}
+
+	if m := HeaderAbsent("K2"); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	} else if desc := m.String(); desc != "!Header(`K2`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+}
+
+func TestHeaderToken(t *testing.T) {
+	if m := HeaderToken("Accept-Encoding"); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := &http.Request{Header: make(http.Header, 2)}
+	req.Header.Add("Accept-Encoding", "gzip;q=0.8, deflate")
+	req.Header.Add("Accept-Encoding", " BR ")
+	req.Header.Set("Cache-Control", `no-cache="Set-Cookie, X-Private", max-age=60`)
+
+	for _, token := range []string{"br", "gzip", "gzip;q=0.8", "Deflate"} {
+		if m := HeaderToken("accept-encoding", token); !m.Match(req) {
+			t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+		}
+	}
+
+	for _, token := range []string{"identity", "gzip;q=0", "deflate;q=1"} {
+		if m := HeaderToken("Accept-Encoding", token); m.Match(req) {
+			t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+		}
+	}
+
+	if m := HeaderToken("Cache-Control", "max-age"); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+
+	if m := HeaderToken("Cache-Control", "max-age=60"); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+
+	if m := HeaderToken("Cache-Control", "max-age=0"); m.Match(req) {
+		t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+	}
+
+	if m := HeaderToken("Cache-Control", "X-Private"); m.Match(req) {
+		t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+	}
+}
+
+func TestHeaderms(t *testing.T) {
+	if m := Headerms(nil); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := &http.Request{Header: make(http.Header, 2)}
+	req.Header.Set("X-Env", "canary")
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	m := Headerms(map[string][]string{
+		"x-env":        {"staging", "canary"},
+		"content-type": {"application/json"},
+	})
+	if !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+
+	expect := "(Header(`Content-Type`,`application/json`) && Header(`X-Env`,`staging`,`canary`))"
+	if desc := m.String(); desc != expect {
+		t.Errorf("expect description '%s', but got '%s'", expect, desc)
+	}
+
+	if m := Headerms(map[string][]string{"X-Env": nil}); !m.Match(req) {
+		t.Errorf("expect match '%v' with '%s', but got not", req.Header, m.String())
+	}
+
+	if m := Headerms(map[string][]string{"X-Env": {"prod"}}); m.Match(req) {
+		t.Errorf("unexpect match '%v' with '%s', but got matched", req.Header, m.String())
+	}
+}
@@ -14,7 +14,45 @@
 
 package matcher
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StrictQuery is used to decide how to treat the malformed query string.
+//
+// If false, the malformed key-value pairs are ignored, which is the same
+// as url.URL.Query. If true, the request with the malformed query string
+// does not match any query matcher.
+var StrictQuery bool
+
+type (
+	queryCacheKey      struct{}
+	queryPairsCacheKey struct{}
+)
+
+type parsedQuery struct {
+	values url.Values
+	err    error
+}
+
+// getQuery returns the parsed query of the request, which is cached
+// in the request-scoped store if the request is returned by WithCache.
+//
+// If StrictQuery is true and the query string is malformed, return false.
+func getQuery(r *http.Request) (url.Values, bool) {
+	query := loadCache(r, queryCacheKey{}, func() parsedQuery {
+		values, err := url.ParseQuery(r.URL.RawQuery)
+		return parsedQuery{values: values, err: err}
+	})
+
+	if query.err != nil && StrictQuery {
+		return nil, false
+	}
+	return query.values, true
+}
 
 // Query returns a new matcher that checks whether the query
 // has the specified key-value argument.
@@ -31,7 +69,12 @@ func Query(key, value string) Matcher {
 
 	desc := kvdesc("Query", key, value)
 	return New(PriorityQuery, desc, func(r *http.Request) bool {
-		values, ok := r.URL.Query()[key]
+		query, ok := getQuery(r)
+		if !ok {
+			return false
+		}
+
+		values, ok := query[key]
 		if !ok || (value != "" && !contains(values, value)) {
 			return false
 		}
@@ -53,8 +96,13 @@ func Querym(querym map[string]string) Matcher {
 	}
 
 	desc := kvsdesc("Query", querym)
-	return New(PriorityQuery*len(querym), desc, func(r *http.Request) bool {
-		query := r.URL.Query()
+	conjs := kvsconjs("Query", querym)
+	return newcs(PriorityQuery*len(querym), desc, conjs, func(r *http.Request) bool {
+		query, ok := getQuery(r)
+		if !ok {
+			return false
+		}
+
 		for key, value := range querym {
 			values, ok := query[key]
 			if !ok || (value != "" && !contains(values, value)) {
@@ -64,3 +112,171 @@ func Querym(querym map[string]string) Matcher {
 		return true
 	})
 }
+
+// Queryms returns a new matcher that checks whether the query
+// has all the specified keys, and the value of each key is any of
+// the specified values.
+//
+// Both keys and values are the exact match.
+// If values is empty, it matches all the requests that has the query key
+// and ignores the query value.
+//
+// If queryms is empty, return nil instead of an error.
+func Queryms(queryms map[string][]string) Matcher {
+	if len(queryms) == 0 {
+		return nil
+	}
+
+	kvs := newkvsm(queryms)
+	desc := kvsmdesc("Query", kvs)
+	conjs := kvsmconjs("Query", kvs)
+	return newcs(PriorityQuery*len(kvs), desc, conjs, func(r *http.Request) bool {
+		query, ok := getQuery(r)
+		if !ok {
+			return false
+		}
+
+		for _, kv := range kvs {
+			values, ok := query[kv.key]
+			if !ok || (len(kv.values) > 0 && !kv.MatchAny(values)) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// QueryValue returns a new matcher that checks whether the values
+// of the query key match the value matcher by the mode.
+//
+// The key is the exact match. If the request has no the query key,
+// it does not match.
+//
+// If key is empty or vm is nil, return nil instead of an error.
+func QueryValue(key string, mode ValuesMode, vm ValueMatcher) Matcher {
+	if key == "" || vm == nil {
+		return nil
+	}
+
+	desc := vmdesc("Query", key, mode, vm)
+	return New(PriorityQuery, desc, func(r *http.Request) bool {
+		query, ok := getQuery(r)
+		return ok && mode.Match(query[key], vm)
+	})
+}
+
+// QueryOptions is the options to match the query key by QueryWith.
+type QueryOptions struct {
+	// If true, the query keys are compared case-insensitively.
+	IgnoreCase bool
+
+	// If true, the query key still containing the percent-encoding
+	// after parsed, such as "user%5Fid" from the double-encoded
+	// "user%255Fid", is unescaped again before compared.
+	UnescapeKey bool
+
+	// Aliases is the alternative keys of the query key,
+	// whose values are collected together in order.
+	Aliases []string
+
+	// Mode is the mode how to match the collected values.
+	Mode ValuesMode
+}
+
+// QueryWith returns a new matcher that checks whether the values
+// of the query key and its aliases match the value matcher by the options.
+//
+// The values of the key and aliases are collected in the order
+// in the query string, so FirstValue and LastValue are applied to
+// all of them.
+//
+// If vm is nil, it only checks whether the query has the key or any alias.
+//
+// The description is like "Query(iu`userId`|`user_id`,last `1`)",
+// where "i" represents IgnoreCase and "u" represents UnescapeKey.
+//
+// If key is empty, return nil instead of an error.
+func QueryWith(key string, vm ValueMatcher, opts QueryOptions) Matcher {
+	if key == "" {
+		return nil
+	}
+
+	keys := append([]string{key}, opts.Aliases...)
+	keydesc := fmt.Sprintf("`%s`", strings.Join(keys, "`|`"))
+	if opts.UnescapeKey {
+		keydesc = "u" + keydesc
+	}
+	if opts.IgnoreCase {
+		keydesc = "i" + keydesc
+	}
+
+	var desc string
+	switch {
+	case vm == nil:
+		desc = fmt.Sprintf("Query(%s)", keydesc)
+	case opts.Mode == AnyValue:
+		desc = fmt.Sprintf("Query(%s,%s)", keydesc, vm.String())
+	default:
+		desc = fmt.Sprintf("Query(%s,%s %s)", keydesc, opts.Mode.String(), vm.String())
+	}
+
+	matchkey := func(k string) bool {
+		if opts.UnescapeKey && strings.IndexByte(k, '%') > -1 {
+			if _k, err := url.QueryUnescape(k); err == nil {
+				k = _k
+			}
+		}
+
+		for _, key := range keys {
+			if k == key || (opts.IgnoreCase && strings.EqualFold(k, key)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return New(PriorityQuery, desc, func(r *http.Request) bool {
+		if _, ok := getQuery(r); !ok {
+			return false
+		}
+
+		var values []string
+		for _, pair := range getQueryPairs(r) {
+			if matchkey(pair[0]) {
+				if vm == nil {
+					return true
+				}
+				values = append(values, pair[1])
+			}
+		}
+
+		return vm != nil && opts.Mode.Match(values, vm)
+	})
+}
+
+// getQueryPairs returns the key-value pairs of the query in order,
+// which ignores the malformed pairs like url.ParseQuery.
+func getQueryPairs(r *http.Request) [][2]string {
+	return loadCache(r, queryPairsCacheKey{}, func() (pairs [][2]string) {
+		query := r.URL.RawQuery
+		for query != "" {
+			var pair string
+			pair, query, _ = strings.Cut(query, "&")
+			if pair == "" || strings.Contains(pair, ";") {
+				continue
+			}
+
+			key, value, _ := strings.Cut(pair, "=")
+			key, err := url.QueryUnescape(key)
+			if err != nil {
+				continue
+			}
+			if value, err = url.QueryUnescape(value); err != nil {
+				continue
+			}
+
+			pairs = append(pairs, [2]string{key, value})
+		}
+		return
+	})
+}
@@ -61,3 +61,102 @@ func TestQuerym(t *testing.T) {
 		t.Errorf("unexpect match '%s', but got matched", req.URL.RawQuery)
 	}
 }
+
+func TestQueryms(t *testing.T) {
+	if m := Queryms(nil); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := &http.Request{URL: &url.URL{RawQuery: "k1=v1&k2=v2"}}
+	if m := Queryms(map[string][]string{"k1": {"v0", "v1"}, "k2": nil}); !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	} else if desc := m.String(); desc != "(Query(`k1`,`v0`,`v1`) && Query(`k2`))" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m := Queryms(map[string][]string{"k1": {"v2", "v3"}}); m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+
+	if m := Queryms(map[string][]string{"k3": nil}); m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+}
+
+func TestStrictQuery(t *testing.T) {
+	defer func() { StrictQuery = false }()
+
+	req := WithCache(&http.Request{URL: &url.URL{RawQuery: "k1=v1&k2=%zz"}})
+	m := And(Query("k1", "v1"), Queryms(map[string][]string{"k1": nil}))
+	if !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	}
+
+	StrictQuery = true
+	if m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+}
+
+func TestQueryValue(t *testing.T) {
+	if m := QueryValue("", AnyValue, Equal("v")); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	vm, err := Compare("int", ">=", "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &http.Request{URL: &url.URL{RawQuery: "version=3&version=abc"}}
+	if m := QueryValue("version", AnyValue, vm); !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	} else if desc := m.String(); desc != "Query(`version`,int>=`3`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m := QueryValue("version", AllValues, vm); m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+}
+
+func TestQueryWith(t *testing.T) {
+	if m := QueryWith("", nil, QueryOptions{}); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	req := &http.Request{URL: &url.URL{RawQuery: "userid=1&user%255Fid=2&UserId=3"}}
+
+	opts := QueryOptions{IgnoreCase: true, UnescapeKey: true, Aliases: []string{"user_id"}, Mode: LastValue}
+	m := QueryWith("userId", Equal("3"), opts)
+	if !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	} else if desc := m.String(); desc != "Query(iu`userId`|`user_id`,last `3`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	opts.Mode = AllValues
+	if m := QueryWith("userId", Glob("?"), opts); !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	}
+
+	opts.Mode = FirstValue
+	if m := QueryWith("userId", Equal("2"), opts); m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+
+	if m := QueryWith("user_id", nil, QueryOptions{}); m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+	if m := QueryWith("user_id", nil, QueryOptions{UnescapeKey: true}); !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	}
+
+	if m := QueryWith("USERID", nil, QueryOptions{}); m.Match(req) {
+		t.Errorf("unexpect match '%s' with '%s', but got matched", req.URL.RawQuery, m.String())
+	}
+
+	if m := QueryWith("USERID", nil, QueryOptions{IgnoreCase: true}); !m.Match(req) {
+		t.Errorf("expect match '%s' with '%s', but got not", req.URL.RawQuery, m.String())
+	}
+}
@@ -42,8 +42,9 @@ func Method(methods ...string) Matcher {
 		_methods[i] = strings.ToUpper(method)
 	}
 
-	desc := fmt.Sprintf("Method(`%s`)", strings.Join(_methods, "`,`"))
-	return New(PriorityMethod, desc, func(r *http.Request) bool {
+	desc := argsdesc("Method", _methods...)
+	canon := argsdesc("Method", sortedset(_methods)...)
+	return newc(PriorityMethod, desc, canon, func(r *http.Request) bool {
 		return _methods.Match(r.Method)
 	})
 }
@@ -0,0 +1,291 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/xgfone/go-toolkit/netx"
+	"golang.org/x/net/publicsuffix"
+)
+
+// origin is the serialized origin, that's, the scheme, host and port,
+// whose port is defaulted by the scheme if missing.
+type origin struct {
+	scheme string
+	host   string
+	port   string
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	default:
+		return ""
+	}
+}
+
+// parseOrigin parses the origin from the Origin or Referer header value.
+// For the opaque origin "null", return false.
+func parseOrigin(s string) (o origin, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return
+	}
+
+	o.scheme = strings.ToLower(u.Scheme)
+	o.host = strings.ToLower(u.Hostname())
+	if o.port = u.Port(); o.port == "" {
+		o.port = defaultPort(o.scheme)
+	}
+	return o, o.host != ""
+}
+
+// GetScheme is used to customize the scheme of the request, which must be
+// lower case, such as "http" or "https".
+//
+// By default, it only uses r.TLS and r.URL.Scheme, and does not trust
+// the header "X-Forwarded-Proto", which may be forged by the client.
+// Behind the trusted proxy, it may be customized to respect the header.
+var GetScheme = func(r *http.Request) string {
+	switch {
+	case r.TLS != nil:
+		return "https"
+	case r.URL != nil && r.URL.Scheme != "":
+		return strings.ToLower(r.URL.Scheme)
+	default:
+		return "http"
+	}
+}
+
+// getRequestOrigin returns the origin of the request itself,
+// whose scheme is got by GetScheme and whose host is got by GetHost.
+func getRequestOrigin(r *http.Request) (o origin) {
+	o.scheme = GetScheme(r)
+	o.host = GetHost(r)
+	if _, o.port = netx.SplitHostPort(r.Host); o.port == "" {
+		o.port = defaultPort(o.scheme)
+	}
+	return
+}
+
+// originPattern is the pattern to match the origin, such as
+// "https://example.com", "https://*.example.com:8443" or "*.example.com".
+type originPattern struct {
+	raw    string
+	scheme string // "" means "http" or "https".
+	port   string // "" means the default port of the scheme, and "*" means any.
+	host   func(string) bool
+}
+
+func parseOriginPattern(pattern string) (p originPattern, err error) {
+	p.raw = strings.ToLower(strings.TrimSpace(pattern))
+	if p.raw == "null" {
+		return
+	}
+
+	hostport := p.raw
+	if scheme, rest, ok := strings.Cut(hostport, "://"); ok {
+		if scheme == "" {
+			return p, fmt.Errorf("invalid origin pattern '%s': missing scheme", pattern)
+		}
+		p.scheme, hostport = scheme, rest
+	}
+
+	if strings.ContainsAny(hostport, "/?#") {
+		return p, fmt.Errorf("invalid origin pattern '%s': unexpected path", pattern)
+	}
+
+	var host string
+	if _host, ok := strings.CutSuffix(hostport, ":*"); ok {
+		host, p.port = strings.Trim(_host, "[]"), "*"
+	} else {
+		host, p.port = netx.SplitHostPort(hostport)
+	}
+
+	if host == "" || strings.Contains(host, ":") && !strings.HasPrefix(hostport, "[") {
+		return p, fmt.Errorf("invalid origin pattern '%s'", pattern)
+	} else if strings.LastIndexByte(host, '*') > 0 {
+		return p, fmt.Errorf("invalid origin pattern '%s': the wildcard must be leading", pattern)
+	}
+
+	p.host = _buildHostMatcher(host)
+	return
+}
+
+func (p originPattern) Match(o origin, ok bool) bool {
+	switch {
+	case p.host == nil: // "null"
+		return !ok
+	case !ok:
+		return false
+	}
+
+	switch p.scheme {
+	case "":
+		if o.scheme != "http" && o.scheme != "https" {
+			return false
+		}
+	case "*":
+	default:
+		if o.scheme != p.scheme {
+			return false
+		}
+	}
+
+	switch p.port {
+	case "*":
+	case "":
+		if o.port != defaultPort(o.scheme) {
+			return false
+		}
+	default:
+		if o.port != p.port {
+			return false
+		}
+	}
+
+	return p.host(o.host)
+}
+
+func parseOriginPatterns(patterns []string) ([]originPattern, error) {
+	ps := make([]originPattern, len(patterns))
+	for i, pattern := range patterns {
+		p, err := parseOriginPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		ps[i] = p
+	}
+	return ps, nil
+}
+
+func matchOriginPatterns(ps []originPattern, value string) bool {
+	o, ok := parseOrigin(value)
+	for _, p := range ps {
+		if p.Match(o, ok) {
+			return true
+		}
+	}
+	return false
+}
+
+func originPatternsDesc(name string, ps []originPattern) (desc, canon string) {
+	raws := make([]string, len(ps))
+	for i, p := range ps {
+		raws[i] = p.raw
+	}
+	return argsdesc(name, raws...), argsdesc(name, sortedset(raws)...)
+}
+
+// Origin returns a new matcher that checks whether the header "Origin"
+// matches one of the origin patterns.
+//
+// The pattern is in the format "[SCHEME://]HOST[:PORT]", or "null" to match
+// the opaque origin. The host supports the exact or wildcard domain
+// the same as Host, such as "example.com" or "*.example.com".
+// If the scheme is missing, it matches "http" and "https", and "*" matches
+// any scheme. If the port is missing, it matches the default port
+// of the scheme, and "*" matches any port.
+//
+// If origins is empty, return (nil, nil) instead of an error.
+func Origin(origins ...string) (Matcher, error) {
+	return newOriginMatcher("Origin", "Origin", origins)
+}
+
+// Referer is the same as Origin, but matches the origin of the header
+// "Referer", and the path of the referer is ignored.
+//
+// If origins is empty, return (nil, nil) instead of an error.
+func Referer(origins ...string) (Matcher, error) {
+	return newOriginMatcher("Referer", "Referer", origins)
+}
+
+func newOriginMatcher(name, header string, origins []string) (Matcher, error) {
+	if len(origins) == 0 {
+		return nil, nil
+	}
+
+	ps, err := parseOriginPatterns(origins)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, canon := originPatternsDesc(name, ps)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		value := r.Header.Get(header)
+		return value != "" && matchOriginPatterns(ps, value)
+	}), nil
+}
+
+// getSourceOrigin returns the origin of the request source
+// from the header "Origin", or "Referer" if "Origin" is missing.
+func getSourceOrigin(r *http.Request) (origin, bool) {
+	if value := r.Header.Get("Origin"); value != "" {
+		return parseOrigin(value)
+	}
+	if value := r.Header.Get("Referer"); value != "" {
+		return parseOrigin(value)
+	}
+	return origin{}, false
+}
+
+// SameOrigin returns a new matcher that checks whether the origin
+// of the header "Origin", or "Referer" if "Origin" is missing, has the same
+// scheme, host and port as the request itself, whose scheme is got
+// by GetScheme and whose host is got by GetHost.
+func SameOrigin() Matcher {
+	return New(PriorityHeader, "SameOrigin()", func(r *http.Request) bool {
+		o, ok := getSourceOrigin(r)
+		return ok && o == getRequestOrigin(r)
+	})
+}
+
+// SameSite returns a new matcher that checks whether the origin
+// of the header "Origin", or "Referer" if "Origin" is missing, has the same
+// scheme and registrable domain (eTLD+1) as the request itself, whose scheme
+// is got by GetScheme and whose host is got by GetHost.
+//
+// The registrable domain is determined by the public suffix list embedded
+// in golang.org/x/net/publicsuffix. For the IP address or the host without
+// the registrable domain, such as "localhost", the host must be equal.
+func SameSite() Matcher {
+	return New(PriorityHeader, "SameSite()", func(r *http.Request) bool {
+		o, ok := getSourceOrigin(r)
+		if !ok {
+			return false
+		}
+
+		self := getRequestOrigin(r)
+		return o.scheme == self.scheme && registrableDomain(o.host) == registrableDomain(self.host)
+	})
+}
+
+func registrableDomain(host string) string {
+	if _, err := netip.ParseAddr(host); err == nil {
+		return host
+	}
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestOrigin(t *testing.T) {
+	if m, err := Origin(); err != nil || m != nil {
+		t.Errorf("expect (nil, nil), but got (%v, %v)", m, err)
+	}
+
+	for _, pattern := range []string{"://example.com", "https://example.com/path", "https://www.*.com", "https://"} {
+		if m, err := Origin(pattern); err == nil {
+			t.Errorf("expect an error for '%s', but got matcher '%s'", pattern, m.String())
+		}
+	}
+
+	m, err := Origin("https://Example.com", "https://*.example.com:8443", "*://*.example.org:*", "api.example.net", "null")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectdesc := "Origin(`https://example.com`,`https://*.example.com:8443`,`*://*.example.org:*`,`api.example.net`,`null`)"
+	if desc := m.String(); desc != expectdesc {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	newreq := func(header, value string) *http.Request {
+		return &http.Request{Header: http.Header{header: []string{value}}}
+	}
+
+	for _, origin := range []string{
+		"https://example.com",
+		"https://EXAMPLE.com:443",
+		"https://www.example.com:8443",
+		"wss://a.example.org:9000",
+		"http://api.example.net",
+		"https://api.example.net",
+		"null",
+	} {
+		if !m.Match(newreq("Origin", origin)) {
+			t.Errorf("expect match the origin '%s', but got not", origin)
+		}
+	}
+
+	for _, origin := range []string{
+		"http://example.com",
+		"https://example.com:8443",
+		"https://www.example.com",
+		"https://example.com.evil.com",
+		"ftp://api.example.net",
+		"http://api.example.net:8080",
+		"",
+	} {
+		if m.Match(newreq("Origin", origin)) {
+			t.Errorf("unexpect match the origin '%s', but got matched", origin)
+		}
+	}
+
+	m, err = Referer("https://*.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Match(newreq("Referer", "https://www.example.com/path/to?a=1")) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m.Match(newreq("Referer", "https://www.example.org/path")) || m.Match(newreq("Origin", "https://www.example.com")) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
+
+func TestSameOriginAndSite(t *testing.T) {
+	newreq := func(host string, https bool, headers ...string) *http.Request {
+		req := &http.Request{Host: host, URL: &url.URL{Path: "/"}, Header: make(http.Header)}
+		if https {
+			req.TLS = &tls.ConnectionState{}
+		}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		return req
+	}
+
+	sameOrigin, sameSite := SameOrigin(), SameSite()
+	for _, c := range []struct {
+		req        *http.Request
+		sameOrigin bool
+		sameSite   bool
+	}{
+		{newreq("www.example.com", true, "Origin", "https://www.example.com"), true, true},
+		{newreq("www.example.com:443", true, "Origin", "https://www.example.com"), true, true},
+		{newreq("www.example.com", true, "Referer", "https://www.example.com/a"), true, true},
+		{newreq("www.example.com", true, "Origin", "https://api.example.com"), false, true},
+		{newreq("www.example.com", true, "Origin", "http://www.example.com"), false, false},
+		{newreq("www.example.com", true, "Origin", "https://www.example.com:8443"), false, true},
+		{newreq("www.example.com", false, "Origin", "http://www.example.com", "X-Forwarded-Proto", "https"), true, true},
+		{newreq("www.example.com", false, "Origin", "https://www.example.com", "X-Forwarded-Proto", "https"), false, false},
+		{newreq("a.github.io", true, "Origin", "https://b.github.io"), false, false},
+		{newreq("www.example.co.uk", true, "Origin", "https://shop.example.co.uk"), false, true},
+		{newreq("localhost:8080", false, "Origin", "http://localhost:8080"), true, true},
+		{newreq("127.0.0.1", false, "Origin", "http://127.0.0.2"), false, false},
+		{newreq("10.0.0.1", false, "Origin", "http://127.0.0.1"), false, false},
+		{newreq("[::1]:8080", false, "Origin", "http://[::1]:8080"), true, true},
+		{newreq("www.example.com", true, "Origin", "null"), false, false},
+		{newreq("www.example.com", true), false, false},
+	} {
+		if ok := sameOrigin.Match(c.req); ok != c.sameOrigin {
+			t.Errorf("%s with %v: expect SameOrigin %v, but got %v", c.req.Host, c.req.Header, c.sameOrigin, ok)
+		}
+		if ok := sameSite.Match(c.req); ok != c.sameSite {
+			t.Errorf("%s with %v: expect SameSite %v, but got %v", c.req.Host, c.req.Header, c.sameSite, ok)
+		}
+	}
+
+	defer func(get func(*http.Request) string) { GetScheme = get }(GetScheme)
+	GetScheme = func(r *http.Request) string {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			return strings.ToLower(proto)
+		}
+		return "http"
+	}
+
+	req := newreq("www.example.com", false, "Origin", "https://www.example.com", "X-Forwarded-Proto", "https")
+	if !sameOrigin.Match(req) {
+		t.Errorf("expect match '%s' by the custom scheme, but got not", sameOrigin.String())
+	}
+	if req := newreq("www.example.com", false, "Origin", "http://www.example.com", "X-Forwarded-Proto", "https"); sameOrigin.Match(req) {
+		t.Errorf("unexpect match '%s' by the custom scheme, but got matched", sameOrigin.String())
+	}
+}
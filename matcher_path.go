@@ -54,8 +54,9 @@ func Path(paths ...string) Matcher {
 		}
 	}
 
-	desc := fmt.Sprintf("Path(`%s`)", strings.Join(mpaths, "`,`"))
-	return New(PriorityPath*maxlen, desc, func(r *http.Request) bool {
+	desc := argsdesc("Path", mpaths...)
+	canon := argsdesc("Path", sortedset(mpaths)...)
+	return newc(PriorityPath*maxlen, desc, canon, func(r *http.Request) bool {
 		return mpaths.Match(GetPath(r))
 	})
 }
@@ -90,8 +91,9 @@ func PathPrefix(pathPrefixes ...string) Matcher {
 		}
 	}
 
-	desc := fmt.Sprintf("PathPrefix(`%s`)", strings.Join(prefixs, "`,`"))
-	return New(PriorityPathPrefix*maxlen, desc, func(r *http.Request) bool {
+	desc := argsdesc("PathPrefix", prefixs...)
+	canon := argsdesc("PathPrefix", sortedset(prefixs)...)
+	return newc(PriorityPathPrefix*maxlen, desc, canon, func(r *http.Request) bool {
 		return prefixs.Match(GetPath(r), matchpathprefix)
 	})
 }
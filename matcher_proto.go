@@ -0,0 +1,149 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Proto returns a new matcher that checks whether the http protocol version
+// of the request is one of the specified versions.
+//
+// The version supports "HTTP/1.0", "HTTP/1.1", "HTTP/2" and "HTTP/3",
+// which may be prefixed by the comparison operator "=", "!=", ">", ">=",
+// "<" or "<=", such as ">=HTTP/2". It is compared with r.ProtoMajor
+// and r.ProtoMinor.
+//
+// If protos is empty, return (nil, nil) instead of an error.
+func Proto(protos ...string) (Matcher, error) {
+	if len(protos) == 0 {
+		return nil, nil
+	}
+
+	checkers := make([]protoChecker, len(protos))
+	descs := make([]string, len(protos))
+	for i, proto := range protos {
+		checker, err := parseProto(proto)
+		if err != nil {
+			return nil, err
+		}
+		checkers[i] = checker
+		descs[i] = checker.String()
+	}
+
+	desc := argsdesc("Proto", descs...)
+	if len(checkers) == 1 {
+		checker := checkers[0]
+		return New(PriorityProto, desc, func(r *http.Request) bool {
+			return checker.Match(r.ProtoMajor, r.ProtoMinor)
+		}), nil
+	}
+
+	canon := argsdesc("Proto", sortedset(descs)...)
+	return newc(PriorityProto, desc, canon, func(r *http.Request) bool {
+		for _, checker := range checkers {
+			if checker.Match(r.ProtoMajor, r.ProtoMinor) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+type protoChecker struct {
+	op    string
+	major int
+	minor int
+}
+
+func (c protoChecker) String() string {
+	if c.op == "=" {
+		return c.version()
+	}
+	return c.op + c.version()
+}
+
+func (c protoChecker) version() string {
+	// HTTP/2 and HTTP/3 have no the minor version, except the explicit one.
+	if c.major < 2 || c.minor != 0 {
+		return fmt.Sprintf("HTTP/%d.%d", c.major, c.minor)
+	}
+	return fmt.Sprintf("HTTP/%d", c.major)
+}
+
+func (c protoChecker) Match(major, minor int) bool {
+	var cmp int
+	switch {
+	case major < c.major:
+		cmp = -1
+	case major > c.major:
+		cmp = 1
+	case minor < c.minor:
+		cmp = -1
+	case minor > c.minor:
+		cmp = 1
+	}
+
+	switch c.op {
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+func parseProto(proto string) (c protoChecker, err error) {
+	s := strings.TrimSpace(proto)
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if strings.HasPrefix(s, op) {
+			c.op, s = op, strings.TrimSpace(s[len(op):])
+			break
+		}
+	}
+	if c.op == "" {
+		c.op = "="
+	}
+
+	if len(s) < 6 || !strings.EqualFold(s[:5], "HTTP/") {
+		return c, fmt.Errorf("invalid http protocol version '%s'", proto)
+	}
+
+	version := s[5:]
+	major, minor, ok := strings.Cut(version, ".")
+	if c.major, err = strconv.Atoi(major); err != nil || c.major < 1 {
+		return c, fmt.Errorf("invalid http protocol version '%s'", proto)
+	}
+
+	if ok {
+		if c.minor, err = strconv.Atoi(minor); err != nil || c.minor < 0 {
+			return c, fmt.Errorf("invalid http protocol version '%s'", proto)
+		}
+	} else if c.major == 1 {
+		return c, fmt.Errorf("missing the minor version in '%s'", proto)
+	}
+
+	return c, nil
+}
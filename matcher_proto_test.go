@@ -0,0 +1,70 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProto(t *testing.T) {
+	if m, err := Proto(); err != nil {
+		t.Error(err)
+	} else if m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	for _, proto := range []string{"HTTP/1", "HTTP/x", "HTTPS/2", ">>HTTP/2"} {
+		if m, err := Proto(proto); err == nil {
+			t.Errorf("expect an error, but got a matcher '%s'", m.String())
+		}
+	}
+
+	req := &http.Request{ProtoMajor: 1, ProtoMinor: 1}
+	if m, err := Proto("http/1.1"); err != nil {
+		t.Error(err)
+	} else if !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	m, err := Proto("HTTP/1.0", ">=HTTP/2.0")
+	if err != nil {
+		t.Fatal(err)
+	} else if desc := m.String(); desc != "Proto(`HTTP/1.0`,`>=HTTP/2`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	}
+
+	if m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.ProtoMajor, req.ProtoMinor = 1, 0
+	if !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	req.ProtoMajor, req.ProtoMinor = 3, 0
+	if !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m, _ := Proto("HTTP/2.1"); m.String() != "Proto(`HTTP/2.1`)" {
+		t.Errorf("unexpected description '%s'", m.String())
+	}
+
+	if m, _ := Proto("<HTTP/2"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Upgrade returns a new matcher that checks whether the request asks
+// to upgrade to one of the specified protocols, such as "websocket" or "h2c".
+//
+// For HTTP/1.x, the header "Connection" must contain the token "upgrade"
+// and the header "Upgrade" must contain one of the protocols. Both tokens
+// are case-insensitive, and the protocol version such as "/2.0" is ignored
+// if the specified protocol does not contain it.
+//
+// For HTTP/2 and later, it matches the extended CONNECT request
+// whose pseudo-header ":protocol" is one of the protocols.
+//
+// If protocols is empty, it matches the request upgrading to any protocol.
+func Upgrade(protocols ...string) Matcher {
+	_protocols := make([]string, len(protocols))
+	for i, protocol := range protocols {
+		_protocols[i] = strings.ToLower(strings.TrimSpace(protocol))
+	}
+
+	desc := argsdesc("Upgrade", _protocols...)
+	canon := argsdesc("Upgrade", sortedset(_protocols)...)
+	return newc(PriorityUpgrade, desc, canon, func(r *http.Request) bool {
+		return matchUpgrade(r, _protocols)
+	})
+}
+
+// WebSocket returns a new matcher that checks whether the request
+// is a WebSocket opening handshake with the version 13, that's,
+// a HTTP/1.1 GET upgrade request with the header "Sec-WebSocket-Key",
+// or a HTTP/2 extended CONNECT request with the ":protocol" "websocket".
+//
+// If subprotocols is not empty, the header "Sec-WebSocket-Protocol"
+// must also contain one of them.
+func WebSocket(subprotocols ...string) Matcher {
+	prio := PriorityUpgrade
+	if len(subprotocols) > 0 {
+		prio += PriorityHeader
+	}
+
+	desc := argsdesc("WebSocket", subprotocols...)
+	canon := argsdesc("WebSocket", sortedset(subprotocols)...)
+	websocket := []string{"websocket"}
+	return newc(prio, desc, canon, func(r *http.Request) bool {
+		if !matchUpgrade(r, websocket) {
+			return false
+		}
+
+		if r.ProtoMajor < 2 && (r.Method != http.MethodGet || r.Header.Get("Sec-WebSocket-Key") == "") {
+			return false
+		}
+
+		if !hasToken(r.Header.Values("Sec-WebSocket-Version"), func(s string) bool { return s == "13" }) {
+			return false
+		}
+
+		if len(subprotocols) == 0 {
+			return true
+		}
+
+		return hasToken(r.Header.Values("Sec-WebSocket-Protocol"), func(s string) bool {
+			return contains(subprotocols, s)
+		})
+	})
+}
+
+func matchUpgrade(r *http.Request, protocols []string) bool {
+	if r.ProtoMajor >= 2 {
+		if r.Method != http.MethodConnect {
+			return false
+		}
+
+		protocol := r.Header.Get(":protocol")
+		return protocol != "" && (len(protocols) == 0 || matchUpgradeProtocol(protocols, protocol))
+	}
+
+	if !hasToken(r.Header.Values("Connection"), isUpgradeToken) {
+		return false
+	}
+
+	return hasToken(r.Header.Values("Upgrade"), func(token string) bool {
+		return len(protocols) == 0 || matchUpgradeProtocol(protocols, token)
+	})
+}
+
+func isUpgradeToken(token string) bool {
+	return strings.EqualFold(token, "upgrade")
+}
+
+func matchUpgradeProtocol(protocols []string, token string) bool {
+	name, _, _ := strings.Cut(token, "/")
+	for _, protocol := range protocols {
+		if strings.IndexByte(protocol, '/') > -1 {
+			if strings.EqualFold(token, protocol) {
+				return true
+			}
+		} else if strings.EqualFold(name, protocol) {
+			return true
+		}
+	}
+	return false
+}
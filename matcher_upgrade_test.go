@@ -0,0 +1,76 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUpgrade(t *testing.T) {
+	req := &http.Request{Method: http.MethodGet, ProtoMajor: 1, Header: make(http.Header)}
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "h2c, WebSocket")
+
+	if m := Upgrade(); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := Upgrade("websocket"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := Upgrade("HTTP/2.0"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.Header.Set("Connection", "keep-alive")
+	if m := Upgrade("websocket"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	req := &http.Request{Method: http.MethodGet, ProtoMajor: 1, Header: make(http.Header)}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Protocol", "chat, superchat")
+
+	if m := WebSocket(); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := WebSocket("superchat"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	if m := WebSocket("mqtt"); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req.Header.Set("Sec-WebSocket-Version", "8")
+	if m := WebSocket(); m.Match(req) {
+		t.Errorf("unexpect match '%s', but got matched", m.String())
+	}
+
+	req = &http.Request{Method: http.MethodConnect, ProtoMajor: 2, Header: make(http.Header)}
+	req.Header.Set(":protocol", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if m := WebSocket(); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// UserAgentInfo is the classification of the user agent.
+//
+// Family is one of "browser", "bot", "crawler", "library" and "app".
+// OS is one of "windows", "macos", "ios", "android", "chromeos" and "linux".
+// Device is one of "desktop", "mobile", "tablet" and "bot".
+// The empty string means unknown.
+type UserAgentInfo struct {
+	Family string
+	OS     string
+	Device string
+}
+
+// UserAgentRule is a rule to classify the user agent. The rules are checked
+// in turn, and each field is set by the first matched rule which has it.
+type UserAgentRule struct {
+	Pattern string // The regular expression to match the User-Agent header.
+
+	Family string
+	OS     string
+	Device string
+}
+
+// builtinUserAgentRules is the embedded rule set to classify the user agent.
+var builtinUserAgentRules = []UserAgentRule{
+	// Family
+	{Pattern: `(?i)googlebot|bingbot|yandex(bot|images)|baiduspider|duckduckbot|yahoo! slurp|applebot|ahrefsbot|semrushbot|mj12bot|petalbot|sogou|exabot|gptbot|ccbot|crawler|spider`, Family: "crawler", Device: "bot"},
+	{Pattern: `(?i)bot\b|bot/|facebookexternalhit|whatsapp|headlesschrome|phantomjs|pingdom|uptimerobot|lighthouse`, Family: "bot", Device: "bot"},
+	{Pattern: `(?i)^(curl|wget|python-requests|python-urllib|python-httpx|aiohttp|go-http-client|java/|apache-httpclient|okhttp|axios|node-fetch|undici|got |libwww-perl|httpie|postmanruntime|insomnia|guzzlehttp|ruby|rest-client|dart:io)`, Family: "library"},
+	{Pattern: `(?i)cfnetwork|dalvik|; wv\)|\bfban/|\bfbav/|instagram|micromessenger|\bline/`, Family: "app"},
+	{Pattern: `(?i)^mozilla/|^opera`, Family: "browser"},
+
+	// OS
+	{Pattern: `(?i)iphone|ipad|ipod|cfnetwork|\bios\b`, OS: "ios"},
+	{Pattern: `(?i)android|dalvik`, OS: "android"},
+	{Pattern: `(?i)\bcros\b`, OS: "chromeos"},
+	{Pattern: `(?i)windows`, OS: "windows"},
+	{Pattern: `(?i)macintosh|mac os x`, OS: "macos"},
+	{Pattern: `(?i)linux|x11`, OS: "linux"},
+
+	// Device
+	{Pattern: `(?i)ipad|tablet|kindle|silk/|playbook`, Device: "tablet"},
+	{Pattern: `(?i)mobile|iphone|ipod|windows phone|dalvik`, Device: "mobile"},
+	{Pattern: `(?i)android`, Device: "tablet"}, // Android without "Mobile"
+	{Pattern: `(?i)windows|macintosh|x11|\bcros\b|linux`, Device: "desktop"},
+}
+
+type userAgentRule struct {
+	UserAgentRule
+	regexp *regexp.Regexp
+}
+
+// maxCachedUserAgentLen is the maximum length of the cached User-Agent,
+// so that the LRU cache is bounded by the memory, not only the entries.
+const maxCachedUserAgentLen = 512
+
+// UserAgentClassifier is used to classify the user agent by the rules,
+// which caches the classifications per distinct User-Agent in a bounded LRU.
+// The User-Agent longer than 512 bytes is classified but not cached.
+type UserAgentClassifier struct {
+	rules []userAgentRule
+
+	lock  sync.Mutex
+	size  int
+	lru   *list.List
+	cache map[string]*list.Element
+}
+
+type userAgentEntry struct {
+	ua   string
+	info UserAgentInfo
+}
+
+// DefaultUserAgentClassifier is the default classifier used by the UserAgent
+// matchers, which only uses the embedded rules and caches 1024 user agents.
+var DefaultUserAgentClassifier = MustNewUserAgentClassifier(1024)
+
+// NewUserAgentClassifier returns a new user agent classifier, which caches
+// at most cacheSize classifications.
+//
+// The custom rules are checked before the embedded rules.
+func NewUserAgentClassifier(cacheSize int, rules ...UserAgentRule) (*UserAgentClassifier, error) {
+	if cacheSize <= 0 {
+		return nil, fmt.Errorf("invalid user agent cache size %d", cacheSize)
+	}
+
+	c := &UserAgentClassifier{
+		size:  cacheSize,
+		lru:   list.New(),
+		cache: make(map[string]*list.Element, cacheSize),
+		rules: make([]userAgentRule, 0, len(rules)+len(builtinUserAgentRules)),
+	}
+
+	for _, rule := range append(rules[:len(rules):len(rules)], builtinUserAgentRules...) {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user agent rule '%s': %w", rule.Pattern, err)
+		}
+		c.rules = append(c.rules, userAgentRule{UserAgentRule: rule, regexp: re})
+	}
+
+	return c, nil
+}
+
+// MustNewUserAgentClassifier is the same as NewUserAgentClassifier,
+// but panics if there is an error.
+func MustNewUserAgentClassifier(cacheSize int, rules ...UserAgentRule) *UserAgentClassifier {
+	c, err := NewUserAgentClassifier(cacheSize, rules...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Classify classifies the User-Agent header value.
+func (c *UserAgentClassifier) Classify(ua string) (info UserAgentInfo) {
+	if ua == "" {
+		return
+	}
+
+	c.lock.Lock()
+	if e, ok := c.cache[ua]; ok {
+		c.lru.MoveToFront(e)
+		info = e.Value.(userAgentEntry).info
+		c.lock.Unlock()
+		return
+	}
+	c.lock.Unlock()
+
+	for i := range c.rules {
+		rule := &c.rules[i]
+		if (rule.Family == "" || info.Family != "") &&
+			(rule.OS == "" || info.OS != "") &&
+			(rule.Device == "" || info.Device != "") {
+			continue
+		}
+
+		if rule.regexp.MatchString(ua) {
+			if info.Family == "" {
+				info.Family = rule.Family
+			}
+			if info.OS == "" {
+				info.OS = rule.OS
+			}
+			if info.Device == "" {
+				info.Device = rule.Device
+			}
+		}
+	}
+
+	if len(ua) > maxCachedUserAgentLen {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.cache[ua]; !ok {
+		c.cache[ua] = c.lru.PushFront(userAgentEntry{ua: ua, info: info})
+		if c.lru.Len() > c.size {
+			e := c.lru.Back()
+			c.lru.Remove(e)
+			delete(c.cache, e.Value.(userAgentEntry).ua)
+		}
+	}
+
+	return
+}
+
+// ClassifyRequest classifies the user agent of the request, which also
+// consults the client hints "Sec-CH-UA", "Sec-CH-UA-Mobile" and
+// "Sec-CH-UA-Platform" when present.
+func (c *UserAgentClassifier) ClassifyRequest(r *http.Request) UserAgentInfo {
+	info := c.Classify(r.Header.Get("User-Agent"))
+	if info.Family == "bot" || info.Family == "crawler" {
+		return info
+	}
+
+	if info.Family == "" && r.Header.Get("Sec-CH-UA") != "" {
+		info.Family = "browser"
+	}
+
+	switch platform := strings.ToLower(unquote(strings.TrimSpace(r.Header.Get("Sec-CH-UA-Platform")))); platform {
+	case "":
+	case "android", "ios", "linux", "macos", "windows":
+		info.OS = platform
+	case "chrome os", "chromium os":
+		info.OS = "chromeos"
+	}
+
+	switch strings.TrimSpace(r.Header.Get("Sec-CH-UA-Mobile")) {
+	case "?1":
+		info.Device = "mobile"
+	case "?0":
+		if info.Device == "mobile" || info.Device == "" {
+			info.Device = "desktop"
+		}
+	}
+
+	return info
+}
+
+type userAgentCacheKey struct{ classifier *UserAgentClassifier }
+
+// GetUserAgentInfo classifies the user agent of the request
+// by DefaultUserAgentClassifier, and returns the classification,
+// which is cached if the request is returned by WithCache.
+func GetUserAgentInfo(r *http.Request) UserAgentInfo {
+	return DefaultUserAgentClassifier.GetUserAgentInfo(r)
+}
+
+// GetUserAgentInfo is the same as the function GetUserAgentInfo,
+// but classifies the user agent by c.
+func (c *UserAgentClassifier) GetUserAgentInfo(r *http.Request) UserAgentInfo {
+	return loadCache(r, userAgentCacheKey{c}, func() UserAgentInfo {
+		return c.ClassifyRequest(r)
+	})
+}
+
+// UserAgentFamily returns a new matcher that checks whether the family
+// of the user agent is one of the specified families, such as "browser",
+// "bot", "crawler", "library" or "app".
+//
+// The user agent is classified by DefaultUserAgentClassifier.
+//
+// If families is empty, return nil instead of an error.
+func UserAgentFamily(families ...string) Matcher {
+	return newUserAgentMatcher("UserAgentFamily", nil, families, getUserAgentFamily)
+}
+
+// UserAgentOS returns a new matcher that checks whether the OS
+// of the user agent is one of the specified OSes, such as "windows",
+// "macos", "ios", "android", "chromeos" or "linux".
+//
+// The user agent is classified by DefaultUserAgentClassifier.
+//
+// If oses is empty, return nil instead of an error.
+func UserAgentOS(oses ...string) Matcher {
+	return newUserAgentMatcher("UserAgentOS", nil, oses, getUserAgentOS)
+}
+
+// UserAgentDevice returns a new matcher that checks whether the device type
+// of the user agent is one of the specified types, such as "desktop",
+// "mobile", "tablet" or "bot".
+//
+// The user agent is classified by DefaultUserAgentClassifier.
+//
+// If devices is empty, return nil instead of an error.
+func UserAgentDevice(devices ...string) Matcher {
+	return newUserAgentMatcher("UserAgentDevice", nil, devices, getUserAgentDevice)
+}
+
+// UserAgentFamily is the same as the function UserAgentFamily,
+// but classifies the user agent by c, such as with the custom rules.
+func (c *UserAgentClassifier) UserAgentFamily(families ...string) Matcher {
+	return newUserAgentMatcher("UserAgentFamily", c, families, getUserAgentFamily)
+}
+
+// UserAgentOS is the same as the function UserAgentOS,
+// but classifies the user agent by c, such as with the custom rules.
+func (c *UserAgentClassifier) UserAgentOS(oses ...string) Matcher {
+	return newUserAgentMatcher("UserAgentOS", c, oses, getUserAgentOS)
+}
+
+// UserAgentDevice is the same as the function UserAgentDevice,
+// but classifies the user agent by c, such as with the custom rules.
+func (c *UserAgentClassifier) UserAgentDevice(devices ...string) Matcher {
+	return newUserAgentMatcher("UserAgentDevice", c, devices, getUserAgentDevice)
+}
+
+func getUserAgentFamily(i UserAgentInfo) string { return i.Family }
+func getUserAgentOS(i UserAgentInfo) string     { return i.OS }
+func getUserAgentDevice(i UserAgentInfo) string { return i.Device }
+
+// newUserAgentMatcher returns a new user agent matcher. If c is nil,
+// use DefaultUserAgentClassifier when matching the request.
+func newUserAgentMatcher(name string, c *UserAgentClassifier, values []string,
+	get func(UserAgentInfo) string) Matcher {
+	if len(values) == 0 {
+		return nil
+	}
+
+	_values := make([]string, len(values))
+	for i, value := range values {
+		_values[i] = strings.ToLower(value)
+	}
+
+	desc := argsdesc(name, _values...)
+	canon := argsdesc(name, sortedset(_values)...)
+	return newc(PriorityHeader, desc, canon, func(r *http.Request) bool {
+		classifier := c
+		if classifier == nil {
+			classifier = DefaultUserAgentClassifier
+		}
+
+		value := get(classifier.GetUserAgentInfo(r))
+		return value != "" && contains(_values, value)
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUserAgentClassifier(t *testing.T) {
+	c := MustNewUserAgentClassifier(2)
+	for ua, expect := range map[string]UserAgentInfo{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36":           {"browser", "windows", "desktop"},
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15": {"browser", "macos", "desktop"},
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148":         {"browser", "ios", "mobile"},
+		"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148":                  {"browser", "ios", "tablet"},
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36":     {"browser", "android", "mobile"},
+		"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36":            {"browser", "android", "tablet"},
+		"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36":            {"browser", "chromeos", "desktop"},
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)":                                              {"crawler", "", "bot"},
+		"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)":                                                     {"bot", "", "bot"},
+		"facebookexternalhit/1.1":                {"bot", "", "bot"},
+		"curl/8.4.0":                             {"library", "", ""},
+		"python-requests/2.31.0":                 {"library", "", ""},
+		"Go-http-client/1.1":                     {"library", "", ""},
+		"MyApp/1.0 CFNetwork/1474 Darwin/23.0.0": {"app", "ios", ""},
+		"Dalvik/2.1.0 (Linux; U; Android 14; Pixel 8 Build/UD1A)": {"app", "android", "mobile"},
+		"": {},
+	} {
+		if info := c.Classify(ua); info != expect {
+			t.Errorf("%s: expect %+v, but got %+v", ua, expect, info)
+		}
+	}
+
+	if n := c.lru.Len(); n != 2 {
+		t.Errorf("expect 2 cached user agents, but got %d", n)
+	}
+
+	c = MustNewUserAgentClassifier(8, UserAgentRule{Pattern: `^MyApp/`, Family: "app", Device: "mobile"})
+	if info := c.Classify("MyApp/1.0 (Linux)"); info != (UserAgentInfo{"app", "linux", "mobile"}) {
+		t.Errorf("unexpected classification by the custom rule: %+v", info)
+	}
+
+	longua := "Mozilla/5.0 (Windows NT 10.0) " + strings.Repeat("x", maxCachedUserAgentLen)
+	if info := c.Classify(longua); info != (UserAgentInfo{"browser", "windows", "desktop"}) {
+		t.Errorf("unexpected classification of the long user agent: %+v", info)
+	} else if _, ok := c.cache[longua]; ok {
+		t.Errorf("unexpect the long user agent is cached")
+	}
+
+	if _, err := NewUserAgentClassifier(8, UserAgentRule{Pattern: "("}); err == nil {
+		t.Errorf("expect an error for the invalid rule, but got nil")
+	}
+	if _, err := NewUserAgentClassifier(0); err == nil {
+		t.Errorf("expect an error for the invalid cache size, but got nil")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	if m := UserAgentFamily(); m != nil {
+		t.Errorf("expect nil, but got matcher '%s'", m.String())
+	}
+
+	newreq := func(headers ...string) *http.Request {
+		req := &http.Request{Header: make(http.Header)}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		return WithCache(req)
+	}
+
+	bots := UserAgentFamily("Crawler", "bot")
+	if desc := bots.String(); desc != "UserAgentFamily(`crawler`,`bot`)" {
+		t.Errorf("unexpected description '%s'", desc)
+	} else if desc := Canonical(bots); desc != "UserAgentFamily(`bot`,`crawler`)" {
+		t.Errorf("unexpected canonical description '%s'", desc)
+	}
+
+	if !bots.Match(newreq("User-Agent", "Mozilla/5.0 (compatible; bingbot/2.0)")) {
+		t.Errorf("expect match '%s', but got not", bots.String())
+	}
+	if bots.Match(newreq("User-Agent", "curl/8.4.0")) || bots.Match(newreq()) {
+		t.Errorf("unexpect match '%s', but got matched", bots.String())
+	}
+
+	// The reduced User-Agent with the client hints.
+	req := newreq(
+		"User-Agent", "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36",
+		"Sec-CH-UA", `"Chromium";v="120", "Not_A Brand";v="8"`,
+		"Sec-CH-UA-Mobile", "?1",
+		"Sec-CH-UA-Platform", `"Android"`,
+	)
+	if m := UserAgentDevice("mobile"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+	if m := UserAgentOS("android"); !m.Match(req) {
+		t.Errorf("expect match '%s', but got not", m.String())
+	}
+
+	custom := MustNewUserAgentClassifier(8, UserAgentRule{Pattern: `^MyApp/`, Family: "app"})
+	req = newreq("User-Agent", "MyApp/2.0")
+	if m := custom.UserAgentFamily("app"); !m.Match(req) {
+		t.Errorf("expect match '%s' by the custom classifier, but got not", m.String())
+	}
+	if m := UserAgentFamily("app"); m.Match(req) {
+		t.Errorf("unexpect match '%s' by the default classifier, but got matched", m.String())
+	}
+
+	req = newreq("Sec-CH-UA", `"Chromium";v="120"`, "Sec-CH-UA-Mobile", "?0", "Sec-CH-UA-Platform", `"macOS"`)
+	if info := GetUserAgentInfo(req); info != (UserAgentInfo{"browser", "macos", "desktop"}) {
+		t.Errorf("unexpected classification by the client hints: %+v", info)
+	}
+}
@@ -18,15 +18,22 @@ import (
 	"bytes"
 	"fmt"
 	"net/netip"
+	"sort"
 	"strings"
 )
 
 const (
+	PriorityAuth       = 1
 	PriorityQuery      = 1
+	PriorityBody       = 2
 	PriorityHeader     = 4
+	PriorityCookie     = 4
+	PriorityUpgrade    = 10
 	PriorityClientIP   = 20
 	PriorityServerIP   = 20
+	PriorityProto      = 30
 	PriorityMethod     = 40
+	PriorityGRPC       = 45
 	PriorityPathPrefix = 50
 	PriorityPath       = 500
 	PriorityHost       = 5000
@@ -41,6 +48,90 @@ func contains(vs []string, s string) bool {
 	return false
 }
 
+// hasToken reports whether any of the comma-separated tokens in values
+// matches the token by the match function, which respects the quoted
+// strings by the list syntax of RFC 9110. Each token is trimmed
+// the leading and trailing whitespaces, and the empty token is ignored.
+func hasToken(values []string, match func(token string) bool) bool {
+	for _, value := range values {
+		for value != "" {
+			var token string
+			token, value, _ = cutQuoted(value, ',')
+			if token = strings.TrimSpace(token); token != "" && match(token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitTokens splits the comma-separated tokens in values.
+func splitTokens(values []string) (tokens []string) {
+	hasToken(values, func(token string) bool {
+		tokens = append(tokens, token)
+		return false
+	})
+	return
+}
+
+// cutQuoted is the same as strings.Cut, but the separator in the quoted
+// string, which supports the backslash escape, is ignored.
+func cutQuoted(s string, sep byte) (before, after string, found bool) {
+	var quoted bool
+	for i, _len := 0, len(s); i < _len; i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// unquote removes the surrounding double quotes of the quoted string
+// and unescapes the backslash escapes. If s is not quoted, return itself.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+
+	s = s[1 : len(s)-1]
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, _len := 0, len(s); i < _len; i++ {
+		if s[i] == '\\' && i+1 < _len {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitParams splits the element, such as "gzip;q=0.8", into the name
+// and the parameters, whose names are converted to lower case
+// and the quoted values are unquoted.
+func splitParams(element string) (name string, params [][2]string) {
+	name, element, _ = cutQuoted(element, ';')
+	name = strings.TrimSpace(name)
+	for element != "" {
+		var param string
+		param, element, _ = cutQuoted(element, ';')
+
+		key, value, _ := strings.Cut(param, "=")
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params = append(params, [2]string{key, unquote(strings.TrimSpace(value))})
+		}
+	}
+	return
+}
+
 type (
 	exactFullMatches   []string
 	exactPrefixMatches []string
@@ -80,6 +171,15 @@ func (cs ipcheckers) ContainsAddr(ip netip.Addr) bool {
 	return false
 }
 
+// Strings returns the string representations of the ip prefixes.
+func (cs ipcheckers) Strings() []string {
+	ss := make([]string, len(cs))
+	for i, c := range cs {
+		ss[i] = c.String()
+	}
+	return ss
+}
+
 func newIPCheckers(ips ...string) (cs ipcheckers, err error) {
 	cs = make([]netip.Prefix, len(ips))
 	for i, ip := range ips {
@@ -99,27 +199,131 @@ func newIPCheckers(ips ...string) (cs ipcheckers, err error) {
 	return
 }
 
+// argsdesc returns the description of the matcher with the arguments,
+// such as "Method(`GET`,`POST`)".
+func argsdesc(name string, args ...string) string {
+	if len(args) == 0 {
+		return name + "()"
+	}
+	return fmt.Sprintf("%s(`%s`)", name, strings.Join(args, "`,`"))
+}
+
+// optdesc returns the description of the named argument of the matcher,
+// such as "header=`X-Api-Key`".
+func optdesc(name, value string) string {
+	return fmt.Sprintf("%s=`%s`", name, value)
+}
+
+// sortedset returns a new sorted and deduplicated copy of the arguments.
+func sortedset(args []string) []string {
+	set := make([]string, len(args))
+	copy(set, args)
+	sort.Strings(set)
+
+	n := 0
+	for i := range set {
+		if i == 0 || set[i] != set[n-1] {
+			set[n] = set[i]
+			n++
+		}
+	}
+	return set[:n]
+}
+
 func kvsdesc(name string, kvs map[string]string) string {
+	if len(kvs) == 1 {
+		for k, v := range kvs {
+			return kvdesc(name, k, v)
+		}
+	}
+
+	keys := make([]string, 0, len(kvs))
+	for k := range kvs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	buf := bytes.NewBuffer(make([]byte, 0, 18*len(kvs)+2))
 	buf.WriteByte('(')
-	var i int
-	for k, v := range kvs {
+	for i, k := range keys {
 		if i > 0 {
 			buf.WriteString(" && ")
 		}
+		buf.WriteString(kvdesc(name, k, kvs[k]))
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
 
-		if v == "" {
-			fmt.Fprintf(buf, "%s(`%s`)", name, k)
-		} else {
-			fmt.Fprintf(buf, "%s(`%s`,`%s`)", name, k, v)
+// kvsconjs returns the canonical descriptions of the key-value pairs
+// as the conjuncts.
+func kvsconjs(name string, kvs map[string]string) []string {
+	conjs := make([]string, 0, len(kvs))
+	for k, v := range kvs {
+		conjs = append(conjs, kvdesc(name, k, v))
+	}
+	return conjs
+}
+
+type kvm struct {
+	key    string
+	values exactFullMatches
+}
+
+// MatchAny reports whether any of values is one of the expected values.
+func (kv kvm) MatchAny(values []string) bool {
+	for _, value := range values {
+		if kv.values.Match(value) {
+			return true
 		}
+	}
+	return false
+}
+
+// newkvsm converts the multi-value map to a slice sorted by the key.
+func newkvsm(kvs map[string][]string) []kvm {
+	_kvs := make([]kvm, 0, len(kvs))
+	for key, values := range kvs {
+		_kvs = append(_kvs, kvm{key: key, values: append(exactFullMatches(nil), values...)})
+	}
+	sort.Slice(_kvs, func(i, j int) bool { return _kvs[i].key < _kvs[j].key })
+	return _kvs
+}
+
+// kvsmconjs returns the canonical descriptions of the multi-value
+// key-value pairs as the conjuncts, whose values are sorted and deduplicated.
+func kvsmconjs(name string, kvs []kvm) []string {
+	conjs := make([]string, len(kvs))
+	for i, kv := range kvs {
+		conjs[i] = kvmdesc(name, kvm{key: kv.key, values: sortedset(kv.values)})
+	}
+	return conjs
+}
 
-		i++
+func kvsmdesc(name string, kvs []kvm) string {
+	if len(kvs) == 1 {
+		return kvmdesc(name, kvs[0])
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 24*len(kvs)+2))
+	buf.WriteByte('(')
+	for i, kv := range kvs {
+		if i > 0 {
+			buf.WriteString(" && ")
+		}
+		buf.WriteString(kvmdesc(name, kv))
 	}
 	buf.WriteByte(')')
 	return buf.String()
 }
 
+func kvmdesc(name string, kv kvm) string {
+	if len(kv.values) == 0 {
+		return fmt.Sprintf("%s(`%s`)", name, kv.key)
+	}
+	return fmt.Sprintf("%s(`%s`,`%s`)", name, kv.key, strings.Join(kv.values, "`,`"))
+}
+
 func kvdesc(name, key, value string) string {
 	if value == "" {
 		return fmt.Sprintf("%s(`%s`)", name, key)
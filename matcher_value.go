@@ -0,0 +1,190 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ValueMatcher is used to match a string value, such as a header value.
+//
+// The description is used as the value argument of the matcher description,
+// such as "~`^curl/`" in "Header(`User-Agent`,~`^curl/`)".
+type ValueMatcher interface {
+	MatchValue(value string) bool
+	fmt.Stringer
+}
+
+type valueMatcher struct {
+	match func(string) bool
+	desc  string
+}
+
+func (m valueMatcher) String() string               { return m.desc }
+func (m valueMatcher) MatchValue(value string) bool { return m.match(value) }
+
+// NewValueMatcher returns a new value matcher.
+func NewValueMatcher(desc string, match func(value string) bool) ValueMatcher {
+	return valueMatcher{match: match, desc: desc}
+}
+
+// Equal returns a value matcher that checks whether the value is equal to s,
+// whose description is "`s`".
+func Equal(s string) ValueMatcher {
+	return NewValueMatcher(fmt.Sprintf("`%s`", s), func(v string) bool { return v == s })
+}
+
+// EqualFold returns a value matcher that checks whether the value is equal
+// to s case-insensitively, whose description is "i`s`".
+func EqualFold(s string) ValueMatcher {
+	return NewValueMatcher(fmt.Sprintf("i`%s`", s), func(v string) bool {
+		return strings.EqualFold(v, s)
+	})
+}
+
+// Prefix returns a value matcher that checks whether the value has
+// the prefix, whose description is "^`prefix`".
+func Prefix(prefix string) ValueMatcher {
+	return NewValueMatcher(fmt.Sprintf("^`%s`", prefix), func(v string) bool {
+		return strings.HasPrefix(v, prefix)
+	})
+}
+
+// Suffix returns a value matcher that checks whether the value has
+// the suffix, whose description is "$`suffix`".
+func Suffix(suffix string) ValueMatcher {
+	return NewValueMatcher(fmt.Sprintf("$`%s`", suffix), func(v string) bool {
+		return strings.HasSuffix(v, suffix)
+	})
+}
+
+// Contains returns a value matcher that checks whether the value contains
+// the substring, whose description is "*`substr`".
+func Contains(substr string) ValueMatcher {
+	return NewValueMatcher(fmt.Sprintf("*`%s`", substr), func(v string) bool {
+		return strings.Contains(v, substr)
+	})
+}
+
+// Glob returns a value matcher that checks whether the whole value matches
+// the glob pattern, whose description is "g`pattern`".
+//
+// In the pattern, '*' matches any sequence of characters including '/',
+// and '?' matches any single character.
+func Glob(pattern string) ValueMatcher {
+	var b strings.Builder
+	b.Grow(len(pattern) + 8)
+	b.WriteString(`^(?s:`)
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteByte('.')
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`)$`)
+
+	re := regexp.MustCompile(b.String())
+	return NewValueMatcher(fmt.Sprintf("g`%s`", pattern), re.MatchString)
+}
+
+// Regexp returns a value matcher that checks whether the value matches
+// the regular expression, whose description is "~`pattern`".
+func Regexp(pattern string) (ValueMatcher, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return NewValueMatcher(fmt.Sprintf("~`%s`", pattern), re.MatchString), nil
+}
+
+// ValuesMode is the mode how to match the multiple values, such as
+// the multiple values of a header.
+type ValuesMode uint8
+
+const (
+	// AnyValue represents that any of the values matches.
+	AnyValue ValuesMode = iota
+
+	// AllValues represents that all the values match.
+	AllValues
+
+	// FirstValue represents that the first value matches.
+	FirstValue
+
+	// LastValue represents that the last value matches.
+	LastValue
+)
+
+// String returns the string representation of the mode.
+func (m ValuesMode) String() string {
+	switch m {
+	case AnyValue:
+		return "any"
+	case AllValues:
+		return "all"
+	case FirstValue:
+		return "first"
+	case LastValue:
+		return "last"
+	default:
+		return fmt.Sprintf("ValuesMode(%d)", m)
+	}
+}
+
+// Match reports whether the values match the value matcher by the mode.
+//
+// If values is empty, return false.
+func (m ValuesMode) Match(values []string, vm ValueMatcher) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	switch m {
+	case AllValues:
+		for _, value := range values {
+			if !vm.MatchValue(value) {
+				return false
+			}
+		}
+		return true
+
+	case FirstValue:
+		return vm.MatchValue(values[0])
+
+	case LastValue:
+		return vm.MatchValue(values[len(values)-1])
+
+	default:
+		for _, value := range values {
+			if vm.MatchValue(value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func vmdesc(name, key string, mode ValuesMode, vm ValueMatcher) string {
+	if mode == AnyValue {
+		return fmt.Sprintf("%s(`%s`,%s)", name, key, vm.String())
+	}
+	return fmt.Sprintf("%s(`%s`,%s %s)", name, key, mode.String(), vm.String())
+}
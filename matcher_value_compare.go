@@ -0,0 +1,242 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type valueType struct {
+	parse   func(string) (any, error)
+	compare func(a, b any) int // nil means only supporting the equality
+}
+
+var valueTypes = map[string]valueType{
+	"int": {
+		parse:   func(s string) (any, error) { return strconv.ParseInt(s, 10, 64) },
+		compare: func(a, b any) int { return cmp.Compare(a.(int64), b.(int64)) },
+	},
+
+	"float": {
+		parse:   func(s string) (any, error) { return strconv.ParseFloat(s, 64) },
+		compare: func(a, b any) int { return cmp.Compare(a.(float64), b.(float64)) },
+	},
+
+	"bool": {
+		parse: func(s string) (any, error) { return strconv.ParseBool(s) },
+	},
+
+	"semver": {
+		parse:   func(s string) (any, error) { return parseSemver(s) },
+		compare: func(a, b any) int { return a.(semver).Compare(b.(semver)) },
+	},
+
+	"duration": {
+		parse:   func(s string) (any, error) { return time.ParseDuration(s) },
+		compare: func(a, b any) int { return cmp.Compare(a.(time.Duration), b.(time.Duration)) },
+	},
+}
+
+// Compare returns a value matcher that parses the value as the type
+// and compares it with the operands by the operator.
+//
+// The supported types are:
+//   - "int": the 64-bit decimal integer, such as "123".
+//   - "float": the 64-bit float, such as "1.5".
+//   - "bool": the bool parsed by strconv.ParseBool, such as "true" or "0".
+//   - "semver": the semantic version, such as "v1.2.3" or "1.2.0-rc.1".
+//   - "duration": the duration parsed by time.ParseDuration, such as "5s".
+//
+// The supported operators are "==", "!=", "<", "<=", ">", ">=",
+// "between" with two operands as the inclusive range, and "in" with
+// one or more operands. But the type "bool" only supports "==", "!="
+// and "in".
+//
+// The value that fails to be parsed as the type does not match, even for
+// the operator "!=". The description is like "int>=`3`", "int between
+// `1`..`5`", or "semver in (`1.0.0`,`2.0.0`)".
+func Compare(_type, op string, operands ...string) (ValueMatcher, error) {
+	vtype, ok := valueTypes[_type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported value type '%s'", _type)
+	}
+
+	values := make([]any, len(operands))
+	for i, operand := range operands {
+		value, err := vtype.parse(operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s operand '%s': %w", _type, operand, err)
+		}
+		values[i] = value
+	}
+
+	compare := vtype.compare
+	if compare == nil {
+		switch op {
+		case "==", "!=", "in":
+			compare = func(a, b any) int {
+				if a == b {
+					return 0
+				}
+				return 1
+			}
+
+		default:
+			return nil, fmt.Errorf("the value type '%s' does not support the operator '%s'", _type, op)
+		}
+	}
+
+	var desc string
+	var match func(any) bool
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		if len(values) != 1 {
+			return nil, fmt.Errorf("the operator '%s' requires 1 operand, but got %d", op, len(values))
+		}
+
+		desc = fmt.Sprintf("%s%s`%s`", _type, op, operands[0])
+		match = newCompareMatch(op, values[0], compare)
+
+	case "between":
+		if len(values) != 2 {
+			return nil, fmt.Errorf("the operator 'between' requires 2 operands, but got %d", len(values))
+		} else if compare(values[0], values[1]) > 0 {
+			return nil, errors.New("the lower bound of 'between' is greater than the upper bound")
+		}
+
+		desc = fmt.Sprintf("%s between `%s`..`%s`", _type, operands[0], operands[1])
+		match = func(v any) bool {
+			return compare(v, values[0]) >= 0 && compare(v, values[1]) <= 0
+		}
+
+	case "in":
+		if len(values) == 0 {
+			return nil, errors.New("the operator 'in' requires at least 1 operand")
+		}
+
+		desc = fmt.Sprintf("%s in (`%s`)", _type, strings.Join(operands, "`,`"))
+		match = func(v any) bool {
+			for _, value := range values {
+				if compare(v, value) == 0 {
+					return true
+				}
+			}
+			return false
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported operator '%s'", op)
+	}
+
+	return NewValueMatcher(desc, func(s string) bool {
+		v, err := vtype.parse(strings.TrimSpace(s))
+		return err == nil && match(v)
+	}), nil
+}
+
+func newCompareMatch(op string, value any, compare func(a, b any) int) func(any) bool {
+	switch op {
+	case "==":
+		return func(v any) bool { return compare(v, value) == 0 }
+	case "!=":
+		return func(v any) bool { return compare(v, value) != 0 }
+	case "<":
+		return func(v any) bool { return compare(v, value) < 0 }
+	case "<=":
+		return func(v any) bool { return compare(v, value) <= 0 }
+	case ">":
+		return func(v any) bool { return compare(v, value) > 0 }
+	default: // ">="
+		return func(v any) bool { return compare(v, value) >= 0 }
+	}
+}
+
+type semver struct {
+	nums [3]uint64
+	pre  []string
+}
+
+// Compare compares two semantic versions by the precedence of SemVer 2.0.0.
+func (v semver) Compare(o semver) int {
+	for i := range v.nums {
+		if c := cmp.Compare(v.nums[i], o.nums[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		a, aerr := strconv.ParseUint(v.pre[i], 10, 64)
+		b, berr := strconv.ParseUint(o.pre[i], 10, 64)
+
+		var c int
+		switch {
+		case aerr == nil && berr == nil:
+			c = cmp.Compare(a, b)
+		case aerr == nil:
+			c = -1
+		case berr == nil:
+			c = 1
+		default:
+			c = strings.Compare(v.pre[i], o.pre[i])
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(len(v.pre), len(o.pre))
+}
+
+// parseSemver parses the semantic version, such as "v1.2.3-rc.1+build",
+// which allows the leading "v" and the missing minor or patch version.
+func parseSemver(s string) (v semver, err error) {
+	version := strings.TrimPrefix(s, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, pre, hasPre := strings.Cut(version, "-")
+
+	nums := strings.Split(version, ".")
+	if len(nums) > 3 {
+		return v, fmt.Errorf("invalid semantic version '%s'", s)
+	}
+
+	for i, num := range nums {
+		if v.nums[i], err = strconv.ParseUint(num, 10, 64); err != nil {
+			return v, fmt.Errorf("invalid semantic version '%s'", s)
+		}
+	}
+
+	if hasPre {
+		if v.pre = strings.Split(pre, "."); contains(v.pre, "") {
+			return v, fmt.Errorf("invalid semantic version '%s'", s)
+		}
+	}
+
+	return
+}
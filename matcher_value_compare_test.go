@@ -0,0 +1,69 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	for _, args := range [][]string{
+		{"string", "==", "a"},
+		{"int", "~", "1"},
+		{"int", "==", "a"},
+		{"int", "==", "1", "2"},
+		{"int", "between", "5", "1"},
+		{"int", "in"},
+		{"bool", ">", "true"},
+	} {
+		if vm, err := Compare(args[0], args[1], args[2:]...); err == nil {
+			t.Errorf("expect an error for %v, but got '%s'", args, vm.String())
+		}
+	}
+
+	tests := []struct {
+		args   []string
+		value  string
+		expect bool
+	}{
+		{[]string{"int", ">=", "3"}, "3", true},
+		{[]string{"int", ">=", "3"}, "2", false},
+		{[]string{"int", "!=", "3"}, "abc", false},
+		{[]string{"int", "between", "1", "5"}, "5", true},
+		{[]string{"int", "between", "1", "5"}, "6", false},
+		{[]string{"int", "in", "1", "3"}, "3", true},
+		{[]string{"float", "<", "1.5"}, "1.25", true},
+		{[]string{"bool", "==", "true"}, "1", true},
+		{[]string{"bool", "!=", "true"}, "false", true},
+		{[]string{"semver", ">=", "1.2.0"}, "v1.10.0", true},
+		{[]string{"semver", ">=", "1.2.0"}, "1.2.0-rc.1", false},
+		{[]string{"semver", ">", "1.2.0-alpha.2"}, "1.2.0-alpha.10", true},
+		{[]string{"semver", ">", "1.2.0-alpha"}, "1.2.0-alpha.1", true},
+		{[]string{"semver", "==", "1.2"}, "1.2.0+build.5", true},
+		{[]string{"duration", "<", "5s"}, "1500ms", true},
+		{[]string{"duration", "<", "5s"}, "1m", false},
+	}
+
+	for _, test := range tests {
+		vm, err := Compare(test.args[0], test.args[1], test.args[2:]...)
+		if err != nil {
+			t.Errorf("%v: %s", test.args, err)
+		} else if result := vm.MatchValue(test.value); result != test.expect {
+			t.Errorf("%s: expect %v for '%s', but got %v", vm.String(), test.expect, test.value, result)
+		}
+	}
+
+	if vm, _ := Compare("int", "between", "1", "5"); vm.String() != "int between `1`..`5`" {
+		t.Errorf("unexpected description '%s'", vm.String())
+	}
+}
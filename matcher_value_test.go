@@ -0,0 +1,51 @@
+// Copyright 2024 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matcher
+
+import "testing"
+
+func TestValueMatcher(t *testing.T) {
+	if _, err := Regexp(`(`); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+
+	re, _ := Regexp(`^v\d+$`)
+	tests := []struct {
+		vm     ValueMatcher
+		value  string
+		expect bool
+	}{
+		{Equal("abc"), "abc", true},
+		{Equal("abc"), "ABC", false},
+		{EqualFold("abc"), "ABC", true},
+		{Prefix("ab"), "abc", true},
+		{Prefix("bc"), "abc", false},
+		{Suffix("bc"), "abc", true},
+		{Suffix("ab"), "abc", false},
+		{Contains("b"), "abc", true},
+		{Contains("d"), "abc", false},
+		{Glob("curl/*"), "curl/8.4.0", true},
+		{Glob("a?c"), "abc", true},
+		{Glob("a.c"), "abc", false},
+		{re, "v12", true},
+		{re, "v1.2", false},
+	}
+
+	for _, test := range tests {
+		if result := test.vm.MatchValue(test.value); result != test.expect {
+			t.Errorf("%s: expect %v for '%s', but got %v", test.vm.String(), test.expect, test.value, result)
+		}
+	}
+}
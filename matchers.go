@@ -39,11 +39,17 @@ func Sort(ms []Matcher) {
 	}
 }
 
+const (
+	andsep = " && "
+	orsep  = " || "
+)
+
 type matchers struct {
 	ms []Matcher
 
 	match func(*http.Request, []Matcher) bool
 	desc  string
+	sep   string
 	prio  int
 }
 
@@ -67,8 +73,8 @@ func And(ms ...Matcher) Matcher {
 
 	Sort(ms)
 	prio := andprio(ms)
-	desc := formatMatchers(" && ", ms)
-	return matchers{ms: ms, match: andmatch, desc: desc, prio: prio}
+	desc := formatMatchers(andsep, ms)
+	return matchers{ms: ms, match: andmatch, desc: desc, sep: andsep, prio: prio}
 }
 
 func andprio(ms []Matcher) (priority int) {
@@ -107,8 +113,8 @@ func Or(ms ...Matcher) Matcher {
 
 	Sort(ms)
 	prio := orprio(ms)
-	desc := formatMatchers(" || ", ms)
-	return matchers{ms: ms, match: ormatch, desc: desc, prio: prio}
+	desc := formatMatchers(orsep, ms)
+	return matchers{ms: ms, match: ormatch, desc: desc, sep: orsep, prio: prio}
 }
 
 func orprio(ms []Matcher) (priority int) {
@@ -156,3 +162,49 @@ func formatMatchers(sep string, matchers []Matcher) string {
 
 	return b.String()
 }
+
+// Canonical returns the canonical description of the matcher,
+// so that the semantically identical matchers have the identical
+// canonical descriptions, which is suitable to be used as a cache key
+// or to compare the rules.
+//
+// For And and Or, the nested matchers of the same kind are flattened,
+// and the sub-matchers are deduplicated and sorted by their canonical
+// descriptions. The map matchers, such as Headerm and Queryms, are
+// the conjunctions of their keys, so they are flattened into And.
+// For the builtin matchers whose arguments are a set, such as Method
+// and Host, the arguments are sorted and deduplicated.
+//
+// If m is nil, return "".
+func Canonical(m Matcher) string {
+	switch v := m.(type) {
+	case nil:
+		return ""
+
+	case matchers:
+		descs := sortedset(canonicalMatchers(nil, v.sep, v.ms))
+		if len(descs) == 1 {
+			return descs[0]
+		}
+		return "(" + strings.Join(descs, v.sep) + ")"
+
+	case interface{ canonical() string }:
+		return v.canonical()
+
+	default:
+		return m.String()
+	}
+}
+
+func canonicalMatchers(descs []string, sep string, ms []Matcher) []string {
+	for _, m := range ms {
+		if _m, ok := m.(matchers); ok && _m.sep == sep {
+			descs = canonicalMatchers(descs, sep, _m.ms)
+		} else if _m, ok := m.(interface{ conjuncts() []string }); ok && sep == andsep && len(_m.conjuncts()) > 0 {
+			descs = append(descs, _m.conjuncts()...)
+		} else {
+			descs = append(descs, Canonical(m))
+		}
+	}
+	return descs
+}
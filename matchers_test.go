@@ -91,3 +91,64 @@ func TestRemoveParentheses(t *testing.T) {
 		t.Errorf("expect '%s', but got '%s'", "abc", s)
 	}
 }
+
+func TestCanonical(t *testing.T) {
+	if s := Canonical(nil); s != "" {
+		t.Errorf("expect '', but got '%s'", s)
+	}
+
+	m1 := And(Method("post", "GET"), Or(Host("B.example.com", "a.example.com"), Path("/a")), Path("/b"))
+	m2 := And(Path("/b"), And(Or(Path("/a"), Host("a.example.com", "b.example.com")), Method("GET", "POST", "GET")))
+
+	expect := "((Host(`a.example.com`,`b.example.com`) || Path(`/a`)) && Method(`GET`,`POST`) && Path(`/b`))"
+	if s := Canonical(m1); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+	if s := Canonical(m2); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+
+	if s := Canonical(Or(Path("/a"), Path("/a"))); s != "Path(`/a`)" {
+		t.Errorf("expect '%s', but got '%s'", "Path(`/a`)", s)
+	}
+
+	for _, ms := range [][]Matcher{
+		{
+			Header("x-env", "a"),
+			Headerm(map[string]string{"x-env": "a"}),
+			Headerms(map[string][]string{"x-env": {"a"}}),
+			HeaderValue("x-env", AnyValue, Equal("a")),
+		},
+		{
+			Query("k", "v"),
+			Querym(map[string]string{"k": "v"}),
+			Queryms(map[string][]string{"k": {"v"}}),
+			QueryValue("k", AnyValue, Equal("v")),
+		},
+		{
+			And(Headerm(map[string]string{"a": "1", "b": "2"}), Path("/x")),
+			And(Header("a", "1"), Header("b", "2"), Path("/x")),
+			And(Path("/x"), Headerms(map[string][]string{"b": {"2"}, "a": {"1", "1"}})),
+		},
+	} {
+		expect := Canonical(ms[0])
+		for _, m := range ms[1:] {
+			if s := Canonical(m); s != expect {
+				t.Errorf("expect '%s', but got '%s'", expect, s)
+			}
+		}
+	}
+
+	expect = "(Header(`A`,`1`) && Header(`B`,`2`) && Path(`/x`))"
+	if s := Canonical(And(Headerm(map[string]string{"a": "1", "b": "2"}), Path("/x"))); s != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, s)
+	}
+
+	m := Headerm(map[string]string{"k3": "", "k1": "v1", "k2": "v2"})
+	expect = "(Header(`K1`,`v1`) && Header(`K2`,`v2`) && Header(`K3`))"
+	for i := 0; i < 10; i++ {
+		if desc := m.String(); desc != expect {
+			t.Fatalf("expect '%s', but got '%s'", expect, desc)
+		}
+	}
+}